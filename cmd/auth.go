@@ -0,0 +1,42 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addAuthFlags adds token file and GitHub App authentication flags to a command, named with flagPrefix
+// (e.g. "source-") and described for the given side (e.g. "Source Organization").
+// They are alternatives to tokenFlag, which the command defines itself.
+func addAuthFlags(cmd *cobra.Command, flagPrefix string, side string, tokenFlag string) {
+	cmd.Flags().String(flagPrefix+"token-file", "", side+" GitHub token file path; alternative to --"+tokenFlag)
+
+	cmd.Flags().String(flagPrefix+"app-id", "", side+" GitHub App ID; use with --"+flagPrefix+"private-key and --"+flagPrefix+"installation-id instead of --"+tokenFlag)
+	cmd.Flags().String(flagPrefix+"private-key", "", side+" GitHub App private key file path or PEM contents")
+	cmd.Flags().String(flagPrefix+"installation-id", "", side+" GitHub App installation ID")
+
+	cmd.MarkFlagsRequiredTogether(flagPrefix+"app-id", flagPrefix+"private-key", flagPrefix+"installation-id")
+	cmd.MarkFlagsMutuallyExclusive(tokenFlag, flagPrefix+"token-file", flagPrefix+"app-id")
+}
+
+// bindAuthFlags binds the credentials of a side ("SOURCE" or "TARGET") from a command's flags.
+// Tokens and private keys are kept in Viper only, so they don't leak into the environment of child processes.
+func bindAuthFlags(cmd *cobra.Command, flagPrefix string, tokenFlag string, envSide string) {
+	viper.Set(envSide+"_TOKEN", cmd.Flag(tokenFlag).Value.String())
+	viper.Set(envSide+"_PRIVATE_KEY", cmd.Flag(flagPrefix+"private-key").Value.String())
+
+	// Set ENV variables
+	os.Setenv("GHMT_"+envSide+"_TOKEN_FILE", cmd.Flag(flagPrefix+"token-file").Value.String())
+	os.Setenv("GHMT_"+envSide+"_APP_ID", cmd.Flag(flagPrefix+"app-id").Value.String())
+	os.Setenv("GHMT_"+envSide+"_INSTALLATION_ID", cmd.Flag(flagPrefix+"installation-id").Value.String())
+
+	// Bind ENV variables in Viper
+	viper.BindEnv(envSide + "_TOKEN_FILE")
+	viper.BindEnv(envSide + "_APP_ID")
+	viper.BindEnv(envSide + "_INSTALLATION_ID")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/pkg/changelog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// changelogCmd represents the changelog command
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Renders the releases of a repository into a Markdown changelog",
+	Long:  "Renders the releases of a repository into a Markdown changelog, with handles and links mapped to the target, and optionally commits it to the target repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		targetOrganization := cmd.Flag("target-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		mappingFile := cmd.Flag("mapping-file").Value.String()
+		numberMappingFile := cmd.Flag("number-mapping-file").Value.String()
+		outputFile := cmd.Flag("output-file").Value.String()
+		template := cmd.Flag("template").Value.String()
+		order := cmd.Flag("order").Value.String()
+		skipPrereleases := cmd.Flag("skip-prereleases").Value.String()
+		timeZone := cmd.Flag("time-zone").Value.String()
+		commit := cmd.Flag("commit").Value.String()
+		branch := cmd.Flag("branch").Value.String()
+		commitMessage := cmd.Flag("commit-message").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_MAPPING_FILE", mappingFile)
+		os.Setenv("GHMT_NUMBER_MAPPING_FILE", numberMappingFile)
+		os.Setenv("GHMT_OUTPUT_FILE", outputFile)
+		os.Setenv("GHMT_TEMPLATE", template)
+		os.Setenv("GHMT_ORDER", order)
+		os.Setenv("GHMT_SKIP_PRERELEASES", skipPrereleases)
+		os.Setenv("GHMT_TIME_ZONE", timeZone)
+		os.Setenv("GHMT_COMMIT", commit)
+		os.Setenv("GHMT_BRANCH", branch)
+		os.Setenv("GHMT_COMMIT_MESSAGE", commitMessage)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("TARGET_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("MAPPING_FILE")
+		viper.BindEnv("NUMBER_MAPPING_FILE")
+		viper.BindEnv("OUTPUT_FILE")
+		viper.BindEnv("TEMPLATE")
+		viper.BindEnv("ORDER")
+		viper.BindEnv("SKIP_PRERELEASES")
+		viper.BindEnv("TIME_ZONE")
+		viper.BindEnv("COMMIT")
+		viper.BindEnv("BRANCH")
+		viper.BindEnv("COMMIT_MESSAGE")
+
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call generatechangelog
+		changelog.GenerateChangelog()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	// Flags
+	changelogCmd.Flags().StringP("source-organization", "s", "", "Source Organization of the repository; required unless --repository includes its owner")
+
+	changelogCmd.Flags().StringP("target-organization", "t", "", "Target Organization links are rewritten to and the changelog is committed to (optional)")
+
+	changelogCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: repo")
+
+	changelogCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token, used with --commit (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: repo")
+
+	addAuthFlags(changelogCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(changelogCmd, "target-", "Target Organization", "target-token")
+
+	changelogCmd.Flags().StringP("repository", "r", "", "repository to render the changelog of")
+	changelogCmd.MarkFlagRequired("repository")
+
+	changelogCmd.Flags().StringP("mapping-file", "m", "", "Mapping file path to use for mapping members handles (optional)")
+
+	changelogCmd.Flags().String("number-mapping-file", "", "Mapping file path of source to target issue/PR numbers used to renumber references, with --target-organization (optional)")
+
+	changelogCmd.Flags().StringP("output-file", "o", "CHANGELOG.md", "File path the changelog is written to, and committed to in the target repository with --commit")
+
+	changelogCmd.Flags().String("template", "", "Go text/template file path used to render the changelog (optional)")
+
+	changelogCmd.Flags().String("order", "newest", "Order of the releases: newest or oldest first")
+
+	changelogCmd.Flags().Bool("skip-prereleases", false, "Leave prereleases out of the changelog")
+
+	changelogCmd.Flags().String("time-zone", "UTC", "IANA time zone used to render release dates Ex. America/Chicago")
+
+	changelogCmd.Flags().Bool("commit", false, "Commit the changelog to the target repository through the contents API")
+
+	changelogCmd.Flags().String("branch", "", "Branch the changelog is committed to (optional) Defaults to the default branch of the target repository")
+
+	changelogCmd.Flags().String("commit-message", "Add changelog of migrated releases", "Message of the changelog commit")
+
+	changelogCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+}
@@ -0,0 +1,43 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addDiscoveryFlags adds flags to select repositories from the whole source organization,
+// as an alternative to the command's --repository and --repository-list-file flags.
+func addDiscoveryFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all-repositories", false, "Discover repositories of the source organization instead of using --repository or --repository-list-file")
+	cmd.Flags().String("visibility", "all", "With --all-repositories, only include repositories with this visibility: all, public, private or internal")
+	cmd.Flags().String("archived", "include", "With --all-repositories, whether to include, exclude or only select archived repositories: include, exclude or only")
+	cmd.Flags().String("topics", "", "With --all-repositories, comma-separated topics repositories must all have")
+	cmd.Flags().String("name-pattern", "", "With --all-repositories, regular expression repository names must match")
+	cmd.Flags().Bool("with-releases", false, "With --all-repositories, only include repositories with at least one release")
+
+	cmd.MarkFlagsMutuallyExclusive("repository", "repository-list-file", "all-repositories")
+}
+
+// bindDiscoveryFlags sets and binds the repository discovery ENV variables from a command's flags.
+func bindDiscoveryFlags(cmd *cobra.Command) {
+	// Set ENV variables
+	os.Setenv("GHMT_ALL_REPOSITORIES", cmd.Flag("all-repositories").Value.String())
+	os.Setenv("GHMT_VISIBILITY", cmd.Flag("visibility").Value.String())
+	os.Setenv("GHMT_ARCHIVED", cmd.Flag("archived").Value.String())
+	os.Setenv("GHMT_TOPICS", cmd.Flag("topics").Value.String())
+	os.Setenv("GHMT_NAME_PATTERN", cmd.Flag("name-pattern").Value.String())
+	os.Setenv("GHMT_WITH_RELEASES", cmd.Flag("with-releases").Value.String())
+
+	// Bind ENV variables in Viper
+	viper.BindEnv("ALL_REPOSITORIES")
+	viper.BindEnv("VISIBILITY")
+	viper.BindEnv("ARCHIVED")
+	viper.BindEnv("TOPICS")
+	viper.BindEnv("NAME_PATTERN")
+	viper.BindEnv("WITH_RELEASES")
+}
@@ -20,7 +20,6 @@ var exportCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get parameters
 		organization := cmd.Flag("organization").Value.String()
-		token := cmd.Flag("token").Value.String()
 		filePrefix := cmd.Flag("file-prefix").Value.String()
 		ghHostname := cmd.Flag("hostname").Value.String()
 		repository := cmd.Flag("repository").Value.String()
@@ -31,18 +30,18 @@ var exportCmd = &cobra.Command{
 
 		// Set ENV variables
 		os.Setenv("GHMT_SOURCE_ORGANIZATION", organization)
-		os.Setenv("GHMT_SOURCE_TOKEN", token)
 		os.Setenv("GHMT_OUTPUT_FILE", filePrefix)
 		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
 		os.Setenv("GHMT_REPOSITORY", repository)
 
 		// Bind ENV variables in Viper
 		viper.BindEnv("SOURCE_ORGANIZATION")
-		viper.BindEnv("SOURCE_TOKEN")
 		viper.BindEnv("OUTPUT_FILE")
 		viper.BindEnv("SOURCE_HOSTNAME")
 		viper.BindEnv("REPOSITORY")
 
+		bindAuthFlags(cmd, "", "token", "SOURCE")
+
 		// Call exportCSV
 		export.CreateJSONs()
 	},
@@ -55,8 +54,9 @@ func init() {
 	exportCmd.Flags().StringP("organization", "o", "", "Organization of the repository")
 	exportCmd.MarkFlagRequired("organization")
 
-	exportCmd.Flags().StringP("token", "t", "", "GitHub token")
-	exportCmd.MarkFlagRequired("token")
+	exportCmd.Flags().StringP("token", "t", "", "GitHub token (optional) Defaults to --token-file, GH_SOURCE_PAT or the gh CLI credentials")
+
+	addAuthFlags(exportCmd, "", "Source Organization", "token")
 
 	exportCmd.Flags().StringP("repository", "r", "", "repository to export")
 	exportCmd.MarkFlagRequired("repository")
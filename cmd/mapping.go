@@ -0,0 +1,150 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/pkg/generate"
+	"github.com/mona-actions/gh-migrate-releases/pkg/validate"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// mappingCmd represents the mapping command
+var mappingCmd = &cobra.Command{
+	Use:   "mapping",
+	Short: "Works with member handle mapping files",
+	Long:  "Works with member handle mapping files",
+}
+
+// mappingValidateCmd represents the mapping validate command
+var mappingValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates a mapping file against the target instance",
+	Long:  "Validates a mapping file against the target instance, reporting unknown or suspended target users and, optionally, handles mentioned in source releases that have no mapping",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		mappingFile := cmd.Flag("mapping-file").Value.String()
+		checkMentions := cmd.Flag("check-mentions").Value.String()
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		repositoryList := cmd.Flag("repository-list-file").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_MAPPING_FILE", mappingFile)
+		os.Setenv("GHMT_CHECK_MENTIONS", checkMentions)
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("MAPPING_FILE")
+		viper.BindEnv("CHECK_MENTIONS")
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("REPOSITORY_LIST")
+
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call validatemapping
+		validate.ValidateMapping()
+	},
+}
+
+// mappingGenerateCmd represents the mapping generate command
+var mappingGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generates a draft mapping file from source release bodies",
+	Long:  "Generates a draft mapping file by matching release authors and handles mentioned in source releases to target organization members by email and SAML identity",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		targetOrganization := cmd.Flag("target-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		repositoryList := cmd.Flag("repository-list-file").Value.String()
+		outputFile := cmd.Flag("output-file").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+		os.Setenv("GHMT_OUTPUT_FILE", outputFile)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("TARGET_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("REPOSITORY_LIST")
+		viper.BindEnv("OUTPUT_FILE")
+
+		bindDiscoveryFlags(cmd)
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call generatemapping
+		generate.GenerateMapping()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mappingCmd)
+	mappingCmd.AddCommand(mappingValidateCmd)
+	mappingCmd.AddCommand(mappingGenerateCmd)
+
+	// Flags
+	mappingValidateCmd.Flags().StringP("mapping-file", "m", "", "Mapping file path to validate")
+	mappingValidateCmd.MarkFlagRequired("mapping-file")
+
+	mappingValidateCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: read:user")
+
+	mappingValidateCmd.Flags().Bool("check-mentions", false, "Also report handles mentioned in source release bodies that have no mapping; requires --repository or --repository-list-file")
+
+	mappingValidateCmd.Flags().StringP("source-organization", "s", "", "Source Organization of the repositories to check mentions in")
+
+	mappingValidateCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: read:org, read:user, user:email")
+
+	mappingValidateCmd.Flags().StringP("repository", "r", "", "repository to check mentions in; can't be used with --repository-list")
+
+	mappingValidateCmd.Flags().StringP("repository-list-file", "l", "", "file path that contains list of repositories to check mentions in; can't be used with --repository")
+
+	mappingValidateCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+	addAuthFlags(mappingValidateCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(mappingValidateCmd, "target-", "Target Organization", "target-token")
+
+	// Generate flags
+	mappingGenerateCmd.Flags().StringP("source-organization", "s", "", "Source Organization of the repositories to scan")
+
+	mappingGenerateCmd.Flags().StringP("target-organization", "t", "", "Target Organization to match users in")
+	mappingGenerateCmd.MarkFlagRequired("target-organization")
+
+	mappingGenerateCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: read:org, read:user, user:email")
+
+	mappingGenerateCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: read:org, read:user, admin:org (for SAML identities)")
+
+	mappingGenerateCmd.Flags().StringP("repository", "r", "", "repository to scan; can't be used with --repository-list")
+
+	mappingGenerateCmd.Flags().StringP("repository-list-file", "l", "", "file path that contains list of repositories to scan; can't be used with --repository")
+
+	mappingGenerateCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+	mappingGenerateCmd.Flags().StringP("output-file", "o", "mapping-draft.csv", "Draft mapping file path to write")
+
+	addAuthFlags(mappingGenerateCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(mappingGenerateCmd, "target-", "Target Organization", "target-token")
+
+	addDiscoveryFlags(mappingGenerateCmd)
+
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/pkg/preflight"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// preflightCmd represents the preflight command
+var preflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Checks credentials, repository access and rate limits before a sync",
+	Long:  "Checks token validity and scopes, read access to every source repository, write access to every target repository, target archived state and rate limit headroom, without writing anything",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		targetOrganization := cmd.Flag("target-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		repositoryList := cmd.Flag("repository-list-file").Value.String()
+		unarchiveTemporarily := cmd.Flag("unarchive-temporarily").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+		os.Setenv("GHMT_UNARCHIVE_TEMPORARILY", unarchiveTemporarily)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("TARGET_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("REPOSITORY_LIST")
+		viper.BindEnv("UNARCHIVE_TEMPORARILY")
+
+		bindDiscoveryFlags(cmd)
+		bindTargetFlags(cmd)
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call preflight
+		preflight.Preflight()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(preflightCmd)
+
+	// Flags
+	preflightCmd.Flags().StringP("source-organization", "s", "", "Source Organization to sync releases from")
+
+	preflightCmd.Flags().StringP("target-organization", "t", "", "Target Organization to sync releases to")
+	preflightCmd.MarkFlagRequired("target-organization")
+
+	preflightCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: read:org, read:user, user:email")
+
+	preflightCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: admin:org. With --target-type gitlab, a GitLab token with the api scope, defaulting to --target-token-file or GITLAB_TOKEN")
+
+	preflightCmd.Flags().StringP("repository", "r", "", "repository to check; can't be used with --repository-list")
+
+	preflightCmd.Flags().StringP("repository-list-file", "l", "", "file path that contains list of repositories to check; can't be used with --repository")
+
+	preflightCmd.Flags().Bool("unarchive-temporarily", false, "Don't fail on archived target repositories, as sync will unarchive them temporarily")
+
+	preflightCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+	addAuthFlags(preflightCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(preflightCmd, "target-", "Target Organization", "target-token")
+
+	addTargetFlags(preflightCmd)
+
+	addDiscoveryFlags(preflightCmd)
+
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/pkg/rollback"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Deletes target releases created by a sync",
+	Long:  "Finds the target releases created by sync from the given source repositories, optionally only those of a given run, and deletes them (and optionally their tags) after confirmation",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		targetOrganization := cmd.Flag("target-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		repositoryList := cmd.Flag("repository-list-file").Value.String()
+		runID := cmd.Flag("run-id").Value.String()
+		deleteTags := cmd.Flag("delete-tags").Value.String()
+		dryRun := cmd.Flag("dry-run").Value.String()
+		yes := cmd.Flag("yes").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+		os.Setenv("GHMT_RUN_ID", runID)
+		os.Setenv("GHMT_DELETE_TAGS", deleteTags)
+		os.Setenv("GHMT_DRY_RUN", dryRun)
+		os.Setenv("GHMT_YES", yes)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("TARGET_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("REPOSITORY_LIST")
+		viper.BindEnv("RUN_ID")
+		viper.BindEnv("DELETE_TAGS")
+		viper.BindEnv("DRY_RUN")
+		viper.BindEnv("YES")
+
+		bindTargetFlags(cmd)
+		bindDiscoveryFlags(cmd)
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call rollback
+		rollback.Rollback()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	// Flags
+	rollbackCmd.Flags().StringP("source-organization", "s", "", "Source Organization releases were synced from")
+
+	rollbackCmd.Flags().StringP("target-organization", "t", "", "Target Organization releases were synced to")
+	rollbackCmd.MarkFlagRequired("target-organization")
+
+	rollbackCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Only used with --all-repositories. Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: repo")
+
+	rollbackCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: repo")
+
+	rollbackCmd.Flags().StringP("repository", "r", "", "repository to roll back; can't be used with --repository-list")
+
+	rollbackCmd.Flags().StringP("repository-list-file", "l", "", "file path that contains list of repositories to roll back; can't be used with --repository")
+
+	rollbackCmd.Flags().String("run-id", "", "Only delete releases created by this sync run, as printed at the start of the sync (optional) Defaults to every synced release")
+
+	rollbackCmd.Flags().Bool("delete-tags", false, "Also delete the tags the sync created for the deleted releases")
+
+	rollbackCmd.Flags().Bool("dry-run", false, "List the releases that would be deleted without deleting them")
+
+	rollbackCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+
+	rollbackCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+	addAuthFlags(rollbackCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(rollbackCmd, "target-", "Target Organization", "target-token")
+
+	addGitHubTargetFlag(rollbackCmd)
+
+	addDiscoveryFlags(rollbackCmd)
+
+}
@@ -6,6 +6,9 @@ package cmd
 import (
 	"os"
 
+	"github.com/mona-actions/gh-migrate-releases/internal/logging"
+	"github.com/mona-actions/gh-migrate-releases/internal/progress"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -39,6 +42,12 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 
+	rootCmd.PersistentFlags().String("log-level", "info", "Minimum level of log entries: debug, info, warn or error")
+
+	rootCmd.PersistentFlags().String("log-format", "text", "Format of log entries: text or json")
+
+	rootCmd.PersistentFlags().String("log-file", "", "File path log entries are also written to (optional)")
+
 	// Initialize Cobra
 	cobra.OnInitialize(initConfig)
 }
@@ -49,4 +58,28 @@ func initConfig() {
 
 	// Read in environment variables that match
 	viper.AutomaticEnv()
+
+	// Use plain timestamped output when not running in a terminal
+	progress.Setup()
+
+	// Get logging parameters
+	logLevel := rootCmd.PersistentFlags().Lookup("log-level").Value.String()
+	logFormat := rootCmd.PersistentFlags().Lookup("log-format").Value.String()
+	logFile := rootCmd.PersistentFlags().Lookup("log-file").Value.String()
+
+	// Set ENV variables
+	os.Setenv("GHMT_LOG_LEVEL", logLevel)
+	os.Setenv("GHMT_LOG_FORMAT", logFormat)
+	os.Setenv("GHMT_LOG_FILE", logFile)
+
+	// Bind ENV variables in Viper
+	viper.BindEnv("LOG_LEVEL")
+	viper.BindEnv("LOG_FORMAT")
+	viper.BindEnv("LOG_FILE")
+
+	err := logging.Setup(viper.GetString("LOG_LEVEL"), viper.GetString("LOG_FORMAT"), viper.GetString("LOG_FILE"))
+	if err != nil {
+		pterm.Error.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 }
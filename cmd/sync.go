@@ -20,32 +20,89 @@ var syncCmd = &cobra.Command{
 		// Get parameters
 		sourceOrganization := cmd.Flag("source-organization").Value.String()
 		targetOrganization := cmd.Flag("target-organization").Value.String()
-		sourceToken := cmd.Flag("source-token").Value.String()
-		targetToken := cmd.Flag("target-token").Value.String()
 		ghHostname := cmd.Flag("source-hostname").Value.String()
 		repository := cmd.Flag("repository").Value.String()
 		mappingFile := cmd.Flag("mapping-file").Value.String()
 		repositoryList := cmd.Flag("repository-list-file").Value.String()
+		numberMappingFile := cmd.Flag("number-mapping-file").Value.String()
+		skipMappingValidation := cmd.Flag("skip-mapping-validation").Value.String()
+		footerTemplate := cmd.Flag("footer-template").Value.String()
+		timeZone := cmd.Flag("time-zone").Value.String()
+		noFooter := cmd.Flag("no-footer").Value.String()
+		authorTokenFile := cmd.Flag("author-token-file").Value.String()
+		skipPreflight := cmd.Flag("skip-preflight").Value.String()
+		unarchiveTemporarily := cmd.Flag("unarchive-temporarily").Value.String()
+		discussionCategory := cmd.Flag("discussion-category").Value.String()
+		preserveSourceArchives := cmd.Flag("preserve-source-archives").Value.String()
+		maxDownloadRate := cmd.Flag("max-download-rate").Value.String()
+		maxUploadRate := cmd.Flag("max-upload-rate").Value.String()
+		reportFile := cmd.Flag("report-file").Value.String()
+		issueOps := cmd.Flag("issue-ops").Value.String()
+		issueSuccessLabels := cmd.Flag("issue-success-labels").Value.String()
+		issueFailureLabels := cmd.Flag("issue-failure-labels").Value.String()
+		notifyWebhook := cmd.Flag("notify-webhook").Value.String()
+		notifySecret := cmd.Flag("notify-secret").Value.String()
+		notifyFormat := cmd.Flag("notify-format").Value.String()
 
 		// Set ENV variables
 		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
 		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
-		os.Setenv("GHMT_SOURCE_TOKEN", sourceToken)
-		os.Setenv("GHMT_TARGET_TOKEN", targetToken)
 		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
 		os.Setenv("GHMT_REPOSITORY", repository)
 		os.Setenv("GHMT_MAPPING_FILE", mappingFile)
 		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+		os.Setenv("GHMT_NUMBER_MAPPING_FILE", numberMappingFile)
+		os.Setenv("GHMT_SKIP_MAPPING_VALIDATION", skipMappingValidation)
+		os.Setenv("GHMT_FOOTER_TEMPLATE", footerTemplate)
+		os.Setenv("GHMT_TIME_ZONE", timeZone)
+		os.Setenv("GHMT_NO_FOOTER", noFooter)
+		os.Setenv("GHMT_AUTHOR_TOKEN_FILE", authorTokenFile)
+		os.Setenv("GHMT_SKIP_PREFLIGHT", skipPreflight)
+		os.Setenv("GHMT_UNARCHIVE_TEMPORARILY", unarchiveTemporarily)
+		os.Setenv("GHMT_DISCUSSION_CATEGORY", discussionCategory)
+		os.Setenv("GHMT_PRESERVE_SOURCE_ARCHIVES", preserveSourceArchives)
+		os.Setenv("GHMT_MAX_DOWNLOAD_RATE", maxDownloadRate)
+		os.Setenv("GHMT_MAX_UPLOAD_RATE", maxUploadRate)
+		os.Setenv("GHMT_REPORT_FILE", reportFile)
+		os.Setenv("GHMT_ISSUE_OPS", issueOps)
+		os.Setenv("GHMT_ISSUE_SUCCESS_LABELS", issueSuccessLabels)
+		os.Setenv("GHMT_ISSUE_FAILURE_LABELS", issueFailureLabels)
+		os.Setenv("GHMT_NOTIFY_WEBHOOK", notifyWebhook)
+		os.Setenv("GHMT_NOTIFY_FORMAT", notifyFormat)
+
+		// Set secrets in Viper only
+		viper.Set("NOTIFY_SECRET", notifySecret)
 
 		// Bind ENV variables in Viper
 		viper.BindEnv("SOURCE_ORGANIZATION")
 		viper.BindEnv("TARGET_ORGANIZATION")
-		viper.BindEnv("SOURCE_TOKEN")
-		viper.BindEnv("TARGET_TOKEN")
 		viper.BindEnv("SOURCE_HOSTNAME")
 		viper.BindEnv("REPOSITORY")
 		viper.BindEnv("MAPPING_FILE")
 		viper.BindEnv("REPOSITORY_LIST")
+		viper.BindEnv("NUMBER_MAPPING_FILE")
+		viper.BindEnv("SKIP_MAPPING_VALIDATION")
+		viper.BindEnv("FOOTER_TEMPLATE")
+		viper.BindEnv("TIME_ZONE")
+		viper.BindEnv("NO_FOOTER")
+		viper.BindEnv("AUTHOR_TOKEN_FILE")
+		viper.BindEnv("SKIP_PREFLIGHT")
+		viper.BindEnv("UNARCHIVE_TEMPORARILY")
+		viper.BindEnv("DISCUSSION_CATEGORY")
+		viper.BindEnv("PRESERVE_SOURCE_ARCHIVES")
+		viper.BindEnv("MAX_DOWNLOAD_RATE")
+		viper.BindEnv("MAX_UPLOAD_RATE")
+		viper.BindEnv("REPORT_FILE")
+		viper.BindEnv("ISSUE_OPS")
+		viper.BindEnv("ISSUE_SUCCESS_LABELS")
+		viper.BindEnv("ISSUE_FAILURE_LABELS")
+		viper.BindEnv("NOTIFY_WEBHOOK")
+		viper.BindEnv("NOTIFY_FORMAT")
+
+		bindDiscoveryFlags(cmd)
+		bindTargetFlags(cmd)
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
 
 		// Call syncreleases
 		sync.SyncReleases()
@@ -58,14 +115,15 @@ func init() {
 	// Flags
 	syncCmd.Flags().StringP("source-organization", "s", "", "Source Organization to sync releases from")
 
-	syncCmd.Flags().StringP("target-organization", "t", "", "Target Organization to sync releases from")
-	syncCmd.MarkFlagRequired("target-organization")
+	syncCmd.Flags().StringP("target-organization", "t", "", "Target Organization to sync releases from; required unless given with --issue-ops")
 
-	syncCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token. Scopes: read:org, read:user, user:email")
-	syncCmd.MarkFlagRequired("source-token")
+	syncCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: read:org, read:user, user:email")
 
-	syncCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token. Scopes: admin:org")
-	syncCmd.MarkFlagRequired("target-token")
+	syncCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: admin:org. With --target-type gitlab, a GitLab token with the api scope, defaulting to --target-token-file or GITLAB_TOKEN")
+
+	addAuthFlags(syncCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(syncCmd, "target-", "Target Organization", "target-token")
 
 	syncCmd.Flags().StringP("repository", "r", "", "repository to export/import releases from/to; can't be used with --repository-list")
 
@@ -73,6 +131,48 @@ func init() {
 
 	syncCmd.Flags().StringP("mapping-file", "m", "", "Mapping file path to use for mapping members handles")
 
+	syncCmd.Flags().Bool("unarchive-temporarily", false, "Unarchive archived target repositories for the sync and archive them again afterwards, even if the sync fails")
+
+	syncCmd.Flags().Bool("skip-preflight", false, "Skip checking credentials, repository access and rate limits before syncing")
+
+	syncCmd.Flags().Bool("skip-mapping-validation", false, "Skip checking that mapped handles exist in the target before syncing")
+
+	syncCmd.Flags().String("number-mapping-file", "", "Mapping file path of source to target issue/PR numbers used to renumber references (optional)")
+
+	syncCmd.Flags().String("footer-template", "", "Go text/template file path used to render the source release details appended to each release body (optional)")
+
+	syncCmd.Flags().String("time-zone", "UTC", "IANA time zone used to render dates in the release footer Ex. America/Chicago")
+
+	syncCmd.Flags().Bool("no-footer", false, "Don't append source release details to release bodies")
+
+	syncCmd.Flags().String("author-token-file", "", "CSV file path of target users' tokens (login,token) used to create releases as their mapped original author (optional)")
+
+	syncCmd.Flags().Bool("preserve-source-archives", false, "Upload the source code archives generated by the source host as release assets, so their checksums stay the same")
+
+	syncCmd.Flags().String("max-download-rate", "", "Maximum rate at which assets are downloaded from the source, shared by all downloads (optional) Ex. 500KB, 10MiB")
+
+	syncCmd.Flags().String("max-upload-rate", "", "Maximum rate at which assets are uploaded to the target, shared by all uploads (optional) Ex. 500KB, 10MiB")
+
+	syncCmd.Flags().Bool("issue-ops", false, "In GitHub Actions, read the repositories, organizations, source hostname and mapping not given as flags from the issue form of the triggering issue or the inputs of a workflow_dispatch run")
+
+	syncCmd.Flags().String("issue-success-labels", "", "Comma-separated labels added to the triggering issue when every release was synced (optional)")
+
+	syncCmd.Flags().String("issue-failure-labels", "", "Comma-separated labels added to the triggering issue when any release or repository failed to sync (optional)")
+
+	syncCmd.Flags().String("notify-webhook", "", "URL the sync events are posted to as JSON: run started, repository completed, release failed and run finished (optional)")
+
+	syncCmd.Flags().String("notify-secret", "", "Secret used to sign webhook notifications in the X-Hub-Signature-256 header (optional) Defaults to NOTIFY_WEBHOOK_SECRET")
+
+	syncCmd.Flags().String("notify-format", "json", "Payload format of webhook notifications: json, slack or teams")
+
+	syncCmd.Flags().String("report-file", "", "JSON file path to write the per-repository sync report to (optional, defaults to gh-migrate-releases-<run id>.json in GitHub Actions)")
+
+	syncCmd.Flags().String("discussion-category", "", "Target discussion category for releases whose source discussion category doesn't exist in the target repository (optional)")
+
 	syncCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
 
+	addTargetFlags(syncCmd)
+
+	addDiscoveryFlags(syncCmd)
+
 }
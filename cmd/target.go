@@ -0,0 +1,36 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addTargetFlags adds flags to select the platform releases are synced to.
+func addTargetFlags(cmd *cobra.Command) {
+	cmd.Flags().String("target-type", "github", "Platform of the target organization: github or gitlab, where the target organization is a GitLab group")
+	cmd.Flags().String("target-hostname", "", "GitLab hostname url with --target-type gitlab (optional) Defaults to gitlab.com")
+}
+
+// addGitHubTargetFlag adds the target type flag to commands that only support GitHub targets, so they
+// reject other platforms rather than running against GitHub.
+func addGitHubTargetFlag(cmd *cobra.Command) {
+	cmd.Flags().String("target-type", "github", "Platform of the target organization; only github is supported")
+}
+
+// bindTargetFlags sets and binds the target platform ENV variables from a command's flags.
+func bindTargetFlags(cmd *cobra.Command) {
+	// Set ENV variables
+	os.Setenv("GHMT_TARGET_TYPE", cmd.Flag("target-type").Value.String())
+	if cmd.Flag("target-hostname") != nil {
+		os.Setenv("GHMT_TARGET_HOSTNAME", cmd.Flag("target-hostname").Value.String())
+	}
+
+	// Bind ENV variables in Viper
+	viper.BindEnv("TARGET_TYPE")
+	viper.BindEnv("TARGET_HOSTNAME")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/pkg/verify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Compares source and target releases after a sync",
+	Long:  "Compares source and target releases by tag, reporting missing releases, mismatched name, draft, prerelease and latest flags, tag SHA mismatches and missing or size-mismatched assets",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get parameters
+		sourceOrganization := cmd.Flag("source-organization").Value.String()
+		targetOrganization := cmd.Flag("target-organization").Value.String()
+		ghHostname := cmd.Flag("source-hostname").Value.String()
+		repository := cmd.Flag("repository").Value.String()
+		repositoryList := cmd.Flag("repository-list-file").Value.String()
+
+		// Set ENV variables
+		os.Setenv("GHMT_SOURCE_ORGANIZATION", sourceOrganization)
+		os.Setenv("GHMT_TARGET_ORGANIZATION", targetOrganization)
+		os.Setenv("GHMT_SOURCE_HOSTNAME", ghHostname)
+		os.Setenv("GHMT_REPOSITORY", repository)
+		os.Setenv("GHMT_REPOSITORY_LIST", repositoryList)
+
+		// Bind ENV variables in Viper
+		viper.BindEnv("SOURCE_ORGANIZATION")
+		viper.BindEnv("TARGET_ORGANIZATION")
+		viper.BindEnv("SOURCE_HOSTNAME")
+		viper.BindEnv("REPOSITORY")
+		viper.BindEnv("REPOSITORY_LIST")
+
+		bindTargetFlags(cmd)
+		bindDiscoveryFlags(cmd)
+		bindAuthFlags(cmd, "source-", "source-token", "SOURCE")
+		bindAuthFlags(cmd, "target-", "target-token", "TARGET")
+
+		// Call verifyreleases
+		verify.VerifyReleases()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	// Flags
+	verifyCmd.Flags().StringP("source-organization", "s", "", "Source Organization releases were synced from")
+
+	verifyCmd.Flags().StringP("target-organization", "t", "", "Target Organization releases were synced to")
+	verifyCmd.MarkFlagRequired("target-organization")
+
+	verifyCmd.Flags().StringP("source-token", "a", "", "Source Organization GitHub token (optional) Defaults to --source-token-file, GH_SOURCE_PAT or the gh CLI credentials. Scopes: repo")
+
+	verifyCmd.Flags().StringP("target-token", "b", "", "Target Organization GitHub token (optional) Defaults to --target-token-file, GH_TARGET_PAT or the gh CLI credentials. Scopes: repo")
+
+	verifyCmd.Flags().StringP("repository", "r", "", "repository to verify; can't be used with --repository-list")
+
+	verifyCmd.Flags().StringP("repository-list-file", "l", "", "file path that contains list of repositories to verify; can't be used with --repository")
+
+	verifyCmd.Flags().StringP("source-hostname", "u", "", "GitHub Enterprise source hostname url (optional) Ex. github.example.com")
+
+	addAuthFlags(verifyCmd, "source-", "Source Organization", "source-token")
+
+	addAuthFlags(verifyCmd, "target-", "Target Organization", "target-token")
+
+	addGitHubTargetFlag(verifyCmd)
+
+	addDiscoveryFlags(verifyCmd)
+
+}
@@ -0,0 +1,99 @@
+package actions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Enabled reports whether the tool is running in a GitHub Actions workflow.
+func Enabled() bool {
+	return os.Getenv("CI") == "true" && os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// Error prints an error annotation, shown on the workflow run summary.
+func Error(title string, message string) {
+	annotate("error", title, message)
+}
+
+// Warning prints a warning annotation, shown on the workflow run summary.
+func Warning(title string, message string) {
+	annotate("warning", title, message)
+}
+
+func annotate(level string, title string, message string) {
+	fmt.Printf("::%s title=%s::%s\n", level, escapeProperty(title), escapeData(message))
+}
+
+// escapeData escapes an annotation message as the runner expects.
+func escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeProperty escapes an annotation property, which additionally can't contain ':' or ','.
+func escapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
+
+// AppendSummary appends markdown to the job summary of the step.
+func AppendSummary(markdown string) error {
+	return appendToFile("GITHUB_STEP_SUMMARY", markdown+"\n")
+}
+
+// SetOutputs sets outputs of the step, so later steps can use them.
+func SetOutputs(outputs map[string]string) error {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		value := outputs[name]
+		if !strings.ContainsAny(value, "\r\n") {
+			builder.WriteString(name + "=" + value + "\n")
+			continue
+		}
+
+		// Multiline values are written between delimiters that can't appear in the value
+		delimiter := "ghadelimiter_" + randomHex()
+		builder.WriteString(name + "<<" + delimiter + "\n" + value + "\n" + delimiter + "\n")
+	}
+
+	return appendToFile("GITHUB_OUTPUT", builder.String())
+}
+
+func appendToFile(envVar string, content string) error {
+	filePath := os.Getenv(envVar)
+	if filePath == "" {
+		return fmt.Errorf("%s is not set", envVar)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+	return err
+}
+
+func randomHex() string {
+	value := make([]byte, 8)
+	rand.Read(value)
+	return hex.EncodeToString(value)
+}
+
+// RunURL returns the URL of the workflow run, or an empty string outside of GitHub Actions.
+func RunURL() string {
+	serverURL, repository, runID := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")
+	if serverURL == "" || repository == "" || runID == "" {
+		return ""
+	}
+	return serverURL + "/" + repository + "/actions/runs/" + runID
+}
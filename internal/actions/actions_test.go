@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSetOutputs(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", filePath)
+
+	err := SetOutputs(map[string]string{
+		"total":   "3",
+		"failed":  "1",
+		"details": "line 1\nline 2",
+	})
+	if err != nil {
+		t.Fatalf("SetOutputs returned an error: %v", err)
+	}
+
+	content, _ := os.ReadFile(filePath)
+	pattern := regexp.MustCompile(`^details<<(ghadelimiter_[0-9a-f]+)\nline 1\nline 2\n(ghadelimiter_[0-9a-f]+)\nfailed=1\ntotal=3\n$`)
+	match := pattern.FindStringSubmatch(string(content))
+	if match == nil || match[1] != match[2] {
+		t.Errorf("SetOutputs wrote %q", content)
+	}
+}
+
+func TestAppendSummary(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "summary")
+	t.Setenv("GITHUB_STEP_SUMMARY", filePath)
+
+	AppendSummary("## First")
+	AppendSummary("## Second")
+
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "## First\n## Second\n" {
+		t.Errorf("AppendSummary wrote %q", content)
+	}
+}
+
+func TestAppendSummaryWithoutActions(t *testing.T) {
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	if err := AppendSummary("## Summary"); err == nil {
+		t.Errorf("AppendSummary did not return an error without GITHUB_STEP_SUMMARY")
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	stdout := os.Stdout
+	reader, writer, _ := os.Pipe()
+	os.Stdout = writer
+
+	Error("Release failed: org/repo, v1", "100% failed\nretry")
+	Warning("Tag conflict", "v2")
+
+	writer.Close()
+	os.Stdout = stdout
+	output, _ := io.ReadAll(reader)
+
+	expected := []string{
+		"::error title=Release failed%3A org/repo%2C v1::100%25 failed%0Aretry",
+		"::warning title=Tag conflict::v2",
+	}
+	if lines := strings.Split(strings.TrimSpace(string(output)), "\n"); strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Annotations printed %q, expected %q", lines, expected)
+	}
+}
+
+func TestRunURL(t *testing.T) {
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "mona/migrations")
+	t.Setenv("GITHUB_RUN_ID", "42")
+	if actual := RunURL(); actual != "https://github.com/mona/migrations/actions/runs/42" {
+		t.Errorf("RunURL() = %q", actual)
+	}
+
+	t.Setenv("GITHUB_RUN_ID", "")
+	if actual := RunURL(); actual != "" {
+		t.Errorf("RunURL() outside of a workflow run = %q, expected an empty string", actual)
+	}
+}
@@ -0,0 +1,189 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Event is the workflow event that triggered the run.
+type Event struct {
+	// Name is the event name, e.g. issues, issue_comment or workflow_dispatch
+	Name string
+	// Owner and Repository are the repository the workflow runs in
+	Owner      string
+	Repository string
+	// IssueNumber and IssueBody are set for issue events, 0 and empty otherwise
+	IssueNumber int
+	IssueBody   string
+	// Inputs are the inputs of a workflow_dispatch event
+	Inputs map[string]string
+}
+
+// payload holds the fields used from an event payload.
+type payload struct {
+	Issue *struct {
+		Number int    `json:"number"`
+		Body   string `json:"body"`
+	} `json:"issue"`
+	Comment    json.RawMessage `json:"comment"`
+	Repository *struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Inputs map[string]interface{} `json:"inputs"`
+}
+
+// LoadEvent reads the triggering event from the GITHUB_CONTEXT variable, which workflows set to
+// ${{ toJson(github) }}, or otherwise from the event payload file the runner provides in GITHUB_EVENT_PATH.
+func LoadEvent() (*Event, error) {
+	if githubContext := os.Getenv("GITHUB_CONTEXT"); githubContext != "" {
+		event, err := parseContext([]byte(githubContext))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing GITHUB_CONTEXT: %v", err)
+		}
+		return event, nil
+	}
+
+	eventPath := os.Getenv("GITHUB_EVENT_PATH")
+	if eventPath == "" {
+		return nil, errors.New("neither GITHUB_CONTEXT nor GITHUB_EVENT_PATH is set")
+	}
+	content, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading event payload: %v", err)
+	}
+	event, err := parsePayload(os.Getenv("GITHUB_EVENT_NAME"), os.Getenv("GITHUB_REPOSITORY"), content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing event payload %s: %v", eventPath, err)
+	}
+
+	return event, nil
+}
+
+// parseContext parses the github context of a workflow. A bare event payload, as passed by
+// ${{ toJson(github.event) }}, is accepted as well.
+func parseContext(data []byte) (*Event, error) {
+	var githubContext struct {
+		EventName  string          `json:"event_name"`
+		Repository json.RawMessage `json:"repository"`
+		Event      json.RawMessage `json:"event"`
+	}
+	err := json.Unmarshal(data, &githubContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(githubContext.Event) == 0 {
+		return parsePayload("", "", data)
+	}
+
+	// The context repository is the "owner/name" string, unlike the payload repository object
+	var repository string
+	json.Unmarshal(githubContext.Repository, &repository)
+
+	return parsePayload(githubContext.EventName, repository, githubContext.Event)
+}
+
+// parsePayload parses an event payload. The event name is inferred from the payload when unknown,
+// and the repository, given as "owner/name", is used when the payload doesn't include one.
+func parsePayload(name string, repository string, data []byte) (*Event, error) {
+	var p payload
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{Name: name}
+	if event.Name == "" {
+		switch {
+		case p.Issue != nil && len(p.Comment) > 0:
+			event.Name = "issue_comment"
+		case p.Issue != nil:
+			event.Name = "issues"
+		case p.Inputs != nil:
+			event.Name = "workflow_dispatch"
+		}
+	}
+
+	if p.Repository != nil && p.Repository.Name != "" {
+		event.Owner, event.Repository = p.Repository.Owner.Login, p.Repository.Name
+	} else if owner, name, ok := strings.Cut(repository, "/"); ok {
+		event.Owner, event.Repository = owner, name
+	}
+
+	if p.Issue != nil && (event.Name == "issues" || event.Name == "issue_comment") {
+		event.IssueNumber = p.Issue.Number
+		event.IssueBody = p.Issue.Body
+	}
+
+	if len(p.Inputs) > 0 {
+		event.Inputs = make(map[string]string, len(p.Inputs))
+		for key, value := range p.Inputs {
+			event.Inputs[key] = fmt.Sprint(value)
+		}
+	}
+
+	return event, nil
+}
+
+// Options returns the options given with the event: the fields of the issue form of an issue event,
+// or the inputs of a workflow_dispatch event. Keys are normalized with OptionKey.
+func (e *Event) Options() map[string]string {
+	if e.IssueNumber != 0 {
+		return ParseIssueForm(e.IssueBody)
+	}
+
+	options := make(map[string]string, len(e.Inputs))
+	for key, value := range e.Inputs {
+		options[OptionKey(key)] = strings.TrimSpace(value)
+	}
+	return options
+}
+
+var (
+	optionKeySeparators = regexp.MustCompile(`[^a-z0-9]+`)
+	codeFence           = regexp.MustCompile("(?m)^\\s*```[\\w-]*\\s*$")
+)
+
+// OptionKey normalizes an issue form label or input name, so "Target organization",
+// "target_organization" and "target-organization" are the same option.
+func OptionKey(label string) string {
+	return strings.Trim(optionKeySeparators.ReplaceAllString(strings.ToLower(label), "-"), "-")
+}
+
+// ParseIssueForm returns the fields of an issue created from an issue form, keyed by their
+// normalized label. Fields left empty, which forms render as "_No response_", are omitted,
+// and code fences around a field's value are removed.
+func ParseIssueForm(body string) map[string]string {
+	fields := make(map[string]string)
+
+	var label string
+	var value []string
+	flush := func() {
+		if label == "" {
+			return
+		}
+		content := strings.TrimSpace(codeFence.ReplaceAllString(strings.Join(value, "\n"), ""))
+		if content != "" && content != "_No response_" {
+			fields[OptionKey(label)] = content
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "### ") {
+			flush()
+			label, value = strings.TrimSpace(strings.TrimPrefix(line, "### ")), nil
+			continue
+		}
+		value = append(value, line)
+	}
+	flush()
+
+	return fields
+}
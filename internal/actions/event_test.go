@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		context   string
+		eventName string
+		payload   string
+		expected  Event
+	}{
+		{
+			name:     "issues context",
+			context:  `{"event_name": "issues", "repository": "mona/migrations", "event": {"action": "opened", "issue": {"number": 7, "body": "### Repositories\n\nrepo1"}, "repository": {"name": "migrations", "owner": {"login": "mona"}}}}`,
+			expected: Event{Name: "issues", Owner: "mona", Repository: "migrations", IssueNumber: 7, IssueBody: "### Repositories\n\nrepo1"},
+		},
+		{
+			name:     "issue_comment context",
+			context:  `{"event_name": "issue_comment", "repository": "mona/migrations", "event": {"issue": {"number": 8, "body": "form"}, "comment": {"body": "/sync"}}}`,
+			expected: Event{Name: "issue_comment", Owner: "mona", Repository: "migrations", IssueNumber: 8, IssueBody: "form"},
+		},
+		{
+			name:     "workflow_dispatch context",
+			context:  `{"event_name": "workflow_dispatch", "repository": "mona/migrations", "event": {"inputs": {"target-organization": "target-org", "dry-run": true}}}`,
+			expected: Event{Name: "workflow_dispatch", Owner: "mona", Repository: "migrations", Inputs: map[string]string{"target-organization": "target-org", "dry-run": "true"}},
+		},
+		{
+			name:     "bare issue event payload",
+			context:  `{"action": "opened", "issue": {"number": 9, "body": "form"}, "repository": {"name": "migrations", "owner": {"login": "mona"}}}`,
+			expected: Event{Name: "issues", Owner: "mona", Repository: "migrations", IssueNumber: 9, IssueBody: "form"},
+		},
+		{
+			name:      "event path",
+			eventName: "issue_comment",
+			payload:   `{"issue": {"number": 10, "body": "form"}, "comment": {"body": "/sync"}, "repository": {"name": "migrations", "owner": {"login": "mona"}}}`,
+			expected:  Event{Name: "issue_comment", Owner: "mona", Repository: "migrations", IssueNumber: 10, IssueBody: "form"},
+		},
+		{
+			name:      "push event path",
+			eventName: "push",
+			payload:   `{"ref": "refs/heads/main"}`,
+			expected:  Event{Name: "push", Owner: "mona", Repository: "migrations"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GITHUB_CONTEXT", test.context)
+			t.Setenv("GITHUB_EVENT_NAME", test.eventName)
+			t.Setenv("GITHUB_REPOSITORY", "mona/migrations")
+			eventPath := filepath.Join(t.TempDir(), "event.json")
+			os.WriteFile(eventPath, []byte(test.payload), 0644)
+			t.Setenv("GITHUB_EVENT_PATH", eventPath)
+
+			event, err := LoadEvent()
+			if err != nil {
+				t.Fatalf("LoadEvent returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(*event, test.expected) {
+				t.Errorf("LoadEvent() = %+v, expected %+v", *event, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadEventErrors(t *testing.T) {
+	t.Setenv("GITHUB_CONTEXT", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	if _, err := LoadEvent(); err == nil {
+		t.Errorf("LoadEvent without an event did not return an error")
+	}
+
+	t.Setenv("GITHUB_CONTEXT", "not json")
+	if _, err := LoadEvent(); err == nil {
+		t.Errorf("LoadEvent with an invalid context did not return an error")
+	}
+}
+
+func TestParseIssueForm(t *testing.T) {
+	body := "### Repositories\r\n\r\n```text\r\nrepo1\r\nsource-org/repo2\r\n```\r\n\r\n" +
+		"### Target organization\r\n\r\ntarget-org\r\n\r\n" +
+		"### Mapping file\r\n\r\n_No response_\r\n\r\n" +
+		"### Source_Hostname \r\n\r\nghes.example.com"
+
+	expected := map[string]string{
+		"repositories":        "repo1\nsource-org/repo2",
+		"target-organization": "target-org",
+		"source-hostname":     "ghes.example.com",
+	}
+	if actual := ParseIssueForm(body); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ParseIssueForm() = %q, expected %q", actual, expected)
+	}
+}
+
+func TestEventOptions(t *testing.T) {
+	event := &Event{Name: "workflow_dispatch", Inputs: map[string]string{"target_organization": " target-org ", "Repository": "repo1"}}
+	expected := map[string]string{"target-organization": "target-org", "repository": "repo1"}
+	if actual := event.Options(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Options() = %q, expected %q", actual, expected)
+	}
+
+	event = &Event{Name: "issues", IssueNumber: 1, IssueBody: "### Repository\n\nrepo1"}
+	expected = map[string]string{"repository": "repo1"}
+	if actual := event.Options(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Options() = %q, expected %q", actual, expected)
+	}
+}
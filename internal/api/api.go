@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,12 +10,14 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/gofri/go-github-ratelimit/github_ratelimit"
 	"github.com/google/go-github/v62/github"
 	"github.com/mona-actions/gh-migrate-releases/internal/files"
+	"github.com/mona-actions/gh-migrate-releases/internal/progress"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
 )
@@ -29,11 +30,10 @@ type Release struct {
 
 var tmpDir = "tmp"
 
-func newGHRestClient(token string, hostname string) *github.Client {
+func newGHRestClient(ts oauth2.TokenSource, hostname string) *github.Client {
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	tc := oauth2.NewClient(ctx, ts)
-	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(tc.Transport)
+	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(loggingTransport{next: tc.Transport})
 
 	if err != nil {
 		panic(err)
@@ -53,32 +53,89 @@ func newGHRestClient(token string, hostname string) *github.Client {
 }
 
 func GetSourceRepositoryReleases(owner string, repository string) ([]*github.RepositoryRelease, error) {
-	client := newGHRestClient(viper.GetString("source_token"), viper.GetString("source_hostname"))
+	releases, _, err := GetSourceRepositoryReleasesWithDiscussions(owner, repository)
+	return releases, err
+}
+
+// GetSourceRepositoryReleasesWithDiscussions returns every release of a source repository, along with the
+// number of the discussion linked to each release that has one, keyed by release id.
+func GetSourceRepositoryReleasesWithDiscussions(owner string, repository string) ([]*github.RepositoryRelease, map[int64]int, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("source_hostname"))
 
 	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
 
+	// The discussion url of a release isn't part of github.RepositoryRelease, so releases are listed raw
+	type release struct {
+		github.RepositoryRelease
+		DiscussionURL string `json:"discussion_url"`
+	}
+
 	var allReleases []*github.RepositoryRelease
+	discussions := make(map[int64]int)
 	opts := &github.ListOptions{PerPage: 100}
 
 	for {
-		releases, resp, err := client.Repositories.ListReleases(ctx, owner, repository, opts)
+		u := fmt.Sprintf("repos/%s/%s/releases?per_page=%d&page=%d", owner, repository, opts.PerPage, opts.Page)
+		req, err := client.NewRequest("GET", u, nil)
+		if err != nil {
+			return allReleases, discussions, err
+		}
+		var releases []*release
+		resp, err := client.Do(ctx, req, &releases)
 		if err != nil {
-			return allReleases, fmt.Errorf("unable to get releases: %v", err)
+			return allReleases, discussions, fmt.Errorf("unable to get releases: %w", err)
+		}
+		for _, r := range releases {
+			allReleases = append(allReleases, &r.RepositoryRelease)
+			if r.DiscussionURL == "" {
+				continue
+			}
+			number, err := strconv.Atoi(r.DiscussionURL[strings.LastIndex(r.DiscussionURL, "/")+1:])
+			if err == nil {
+				discussions[r.GetID()] = number
+			}
 		}
-		allReleases = append(allReleases, releases...)
 		if resp.NextPage == 0 {
 			break
 		}
 		opts.Page = resp.NextPage
 	}
 
-	return allReleases, nil
+	return allReleases, discussions, nil
+
+}
+
+// GetSourcePullRequestNumbers returns the numbers of every pull request of a source repository, in any state.
+func GetSourcePullRequestNumbers(owner string, repository string) (map[int]bool, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	numbers := make(map[int]bool)
+	opts := &github.PullRequestListOptions{State: "all", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		pullRequests, resp, err := client.PullRequests.List(ctx, owner, repository, opts)
+		if err != nil {
+			return numbers, fmt.Errorf("unable to get pull requests: %w", err)
+		}
+		for _, pullRequest := range pullRequests {
+			numbers[pullRequest.GetNumber()] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
 
+	return numbers, nil
 }
 
 func DownloadReleaseAssets(asset *github.ReleaseAsset) error {
 
-	token := viper.Get("SOURCE_TOKEN").(string)
+	token, err := accessToken(sourceTokenSource())
+	if err != nil {
+		return err
+	}
 
 	// Download the asset
 
@@ -86,7 +143,7 @@ func DownloadReleaseAssets(asset *github.ReleaseAsset) error {
 	dirName := tmpDir
 	fileName := dirName + "/" + asset.GetName()
 
-	err := os.MkdirAll(dirName, 0755)
+	err = os.MkdirAll(dirName, 0755)
 	if err != nil {
 		return err
 	}
@@ -98,58 +155,40 @@ func DownloadReleaseAssets(asset *github.ReleaseAsset) error {
 	return nil
 }
 
-func DownloadReleaseZip(release *github.RepositoryRelease) error {
-	token := viper.Get("SOURCE_TOKEN").(string)
-	repo := viper.Get("REPOSITORY").(string)
+// SourceArchiveAssets returns the zipball and tarball the source host generated for a release, as assets
+// named like the archives GitHub serves, so they can be downloaded and uploaded like any other asset.
+func SourceArchiveAssets(repository string, release *github.RepositoryRelease) ([]*github.ReleaseAsset, error) {
 	if release.TagName == nil {
-		return errors.New("TagName is nil")
+		return nil, errors.New("TagName is nil")
 	}
 	tag := *release.TagName
 	var tagName string
 
-	url := *release.ZipballURL
-
 	if len(tag) > 1 && tag[0] == 'v' && unicode.IsDigit(rune(tag[1])) {
 		tagName = strings.TrimPrefix(tag, "v")
 	} else {
 		tagName = tag
 	}
 
-	fileName := fmt.Sprintf("%s-%s.zip", repo, tagName)
-
-	err := DownloadFileFromURL(url, fileName, token)
-	if err != nil {
-		return err
+	var assets []*github.ReleaseAsset
+	if release.GetZipballURL() != "" {
+		assets = append(assets, &github.ReleaseAsset{
+			Name:               github.String(fmt.Sprintf("%s-%s.zip", repository, tagName)),
+			Label:              github.String("Source code (zip)"),
+			ContentType:        github.String("application/zip"),
+			BrowserDownloadURL: github.String(release.GetZipballURL()),
+		})
 	}
-
-	return nil
-}
-
-func DownloadReleaseTarball(release *github.RepositoryRelease) error {
-	token := viper.Get("SOURCE_TOKEN").(string)
-	repo := viper.Get("REPOSITORY").(string)
-	if release.TagName == nil {
-		return errors.New("TagName is nil")
-	}
-	tag := *release.TagName
-	var tagName string
-
-	url := *release.TarballURL
-
-	if len(tag) > 1 && tag[0] == 'v' && unicode.IsDigit(rune(tag[1])) {
-		tagName = strings.TrimPrefix(tag, "v")
-	} else {
-		tagName = tag
+	if release.GetTarballURL() != "" {
+		assets = append(assets, &github.ReleaseAsset{
+			Name:               github.String(fmt.Sprintf("%s-%s.tar.gz", repository, tagName)),
+			Label:              github.String("Source code (tar.gz)"),
+			ContentType:        github.String("application/gzip"),
+			BrowserDownloadURL: github.String(release.GetTarballURL()),
+		})
 	}
 
-	fileName := fmt.Sprintf("%s-%s.tar.gz", repo, tagName)
-
-	err := DownloadFileFromURL(url, fileName, token)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return assets, nil
 }
 
 func DownloadFileFromURL(url, fileName, token string) error {
@@ -168,32 +207,45 @@ func DownloadFileFromURL(url, fileName, token string) error {
 	req.Header.Add("Authorization", "Bearer "+token)
 
 	// Get the data
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("error getting file: %v  err:%v", fileName, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP request failed with status code %d, Message: %s", resp.StatusCode, resp.Body)
+		return newRequestError(resp, "error downloading "+filepath.Base(fileName))
+	}
+
+	limiter, err := transferLimiter("MAX_DOWNLOAD_RATE")
+	if err != nil {
+		return err
 	}
 
 	// Write the body to file
-	_, err = io.Copy(out, resp.Body)
+	transfer := progress.StartTransfer("Downloading", filepath.Base(fileName), resp.ContentLength)
+	_, err = io.Copy(out, transfer.Reader(throttle(resp.Body, limiter)))
+	transfer.Finish(err)
 	return err
 }
 
+// CreateRelease creates a release in the target repository with the target credentials.
 func CreateRelease(repository string, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
-	client := newGHRestClient(viper.GetString("TARGET_TOKEN"), "")
+	return createRelease(targetTokenSource(), repository, release)
+}
+
+// CreateReleaseAs creates a release in the target repository, authored by the owner of token.
+func CreateReleaseAs(repository string, release *github.RepositoryRelease, token string) (*github.RepositoryRelease, error) {
+	return createRelease(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), repository, release)
+}
+
+func createRelease(ts oauth2.TokenSource, repository string, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	client := newGHRestClient(ts, "")
 
 	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
 	newRelease, _, err := client.Repositories.CreateRelease(ctx, viper.Get("TARGET_ORGANIZATION").(string), repository, release)
 	if err != nil {
-		if strings.Contains(err.Error(), "already_exists") {
-			return nil, fmt.Errorf("release already exists: %v", release.GetName())
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	return newRelease, nil
@@ -209,6 +261,7 @@ func UploadAssetViaURL(uploadURL string, asset *github.ReleaseAsset) error {
 	if err != nil {
 		return fmt.Errorf("error opening file: %v err: %v", file, err)
 	}
+	defer file.Close()
 
 	// Get the file size
 	stat, err := file.Stat()
@@ -231,30 +284,47 @@ func UploadAssetViaURL(uploadURL string, asset *github.ReleaseAsset) error {
 
 	uploadURLWithParams := fmt.Sprintf("%s?%s", uploadURL, params.Encode())
 
+	limiter, err := transferLimiter("MAX_UPLOAD_RATE")
+	if err != nil {
+		return err
+	}
+
+	token, err := accessToken(targetTokenSource())
+	if err != nil {
+		return err
+	}
+
 	// Create the request
-	req, err := http.NewRequest("POST", uploadURLWithParams, file)
+	transfer := progress.StartTransfer("Uploading", asset.GetName(), stat.Size())
+	req, err := http.NewRequest("POST", uploadURLWithParams, transfer.Reader(throttle(file, limiter)))
 	if err != nil {
+		transfer.Finish(err)
 		return fmt.Errorf("error creating request: %v", err)
 	}
 
 	// Set the headers
 	req.ContentLength = stat.Size()
 	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Authorization", "Bearer "+viper.Get("TARGET_TOKEN").(string))
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", mediaType)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
+		transfer.Finish(err)
 		return fmt.Errorf("error uploading asset to release: %v err: %v", uploadURL, err)
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("error uploading asset to release: %v err: %v", uploadURL, resp.Body)
+		err = newRequestError(resp, "error uploading asset "+asset.GetName())
+		transfer.Finish(err)
+		return err
 	}
+	transfer.Finish(nil)
 
+	// Close before removing, open files can't be removed on Windows
+	file.Close()
 	err = files.RemoveFile(fileName)
 	if err != nil {
 		return fmt.Errorf("error deleting asset from local storage: %v err: %v", asset.Name, err)
@@ -262,35 +332,3 @@ func UploadAssetViaURL(uploadURL string, asset *github.ReleaseAsset) error {
 
 	return nil
 }
-
-func WriteToIssue(owner string, repository string, issueNumber int, comment string) error {
-
-	client := newGHRestClient(viper.GetString("TARGET_TOKEN"), "")
-
-	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
-	_, _, err := client.Issues.CreateComment(ctx, owner, repository, issueNumber, &github.IssueComment{Body: &comment})
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func GetDatafromGitHubContext() (string, string, int, error) {
-	githubContext := os.Getenv("GITHUB_CONTEXT")
-	if githubContext == "" {
-		return "", "", 0, fmt.Errorf("GITHUB_CONTEXT is not set or empty")
-	}
-
-	var issueEvent github.IssueEvent
-
-	err := json.Unmarshal([]byte(githubContext), &issueEvent)
-	if err != nil {
-		return "", "", 0, fmt.Errorf("error unmarshalling GITHUB_CONTEXT: %v", err)
-	}
-	organization := *issueEvent.Repository.Owner.Login
-	repository := *issueEvent.Repository.Name
-	issueNumber := *issueEvent.Issue.Number
-
-	return organization, repository, issueNumber, nil
-}
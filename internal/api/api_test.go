@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestSourceArchiveAssets(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected []string
+	}{
+		{"v1.2.0", []string{"repo-1.2.0.zip", "repo-1.2.0.tar.gz"}},
+		{"release-7", []string{"repo-release-7.zip", "repo-release-7.tar.gz"}},
+		{"version", []string{"repo-version.zip", "repo-version.tar.gz"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			release := &github.RepositoryRelease{
+				TagName:    github.String(test.tag),
+				ZipballURL: github.String("https://api.github.com/repos/org/repo/zipball/" + test.tag),
+				TarballURL: github.String("https://api.github.com/repos/org/repo/tarball/" + test.tag),
+			}
+
+			assets, err := SourceArchiveAssets("repo", release)
+			if err != nil {
+				t.Fatalf("SourceArchiveAssets returned an error: %v", err)
+			}
+			if len(assets) != len(test.expected) {
+				t.Fatalf("SourceArchiveAssets returned %d assets, expected %d", len(assets), len(test.expected))
+			}
+			for i, asset := range assets {
+				if asset.GetName() != test.expected[i] {
+					t.Errorf("Asset %d is named %q, expected %q", i, asset.GetName(), test.expected[i])
+				}
+				if asset.GetContentType() == "" || asset.GetBrowserDownloadURL() == "" {
+					t.Errorf("Asset %q has no content type or download URL", asset.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "ABCD:1234:5678")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	client, _ := github.NewClient(&http.Client{Transport: loggingTransport{next: http.DefaultTransport}}).WithEnterpriseURLs(server.URL, server.URL)
+	_, _, err := client.Repositories.Get(context.Background(), "org", "repo")
+	if err == nil {
+		t.Fatalf("Getting a missing repository did not return an error")
+	}
+
+	if requestID := RequestID(fmt.Errorf("unable to get repository: %w", err)); requestID != "ABCD:1234:5678" {
+		t.Errorf("RequestID returned %q for an API error, expected ABCD:1234:5678", requestID)
+	}
+
+	resp, err := httpClient.Get(server.URL + "/asset")
+	if err != nil {
+		t.Fatalf("Downloading returned an error: %v", err)
+	}
+	resp.Body.Close()
+	if requestID := RequestID(newRequestError(resp, "error downloading asset")); requestID != "ABCD:1234:5678" {
+		t.Errorf("RequestID returned %q for a download error, expected ABCD:1234:5678", requestID)
+	}
+
+	if requestID := RequestID(errors.New("no response")); requestID != "" {
+		t.Errorf("RequestID returned %q for an error without a response", requestID)
+	}
+}
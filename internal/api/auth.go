@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+var (
+	tokenSourcesMu sync.Mutex
+	tokenSources   = make(map[string]oauth2.TokenSource)
+)
+
+// sourceTokenSource returns the credentials used for the source instance.
+func sourceTokenSource() oauth2.TokenSource {
+	return configuredTokenSource("SOURCE", viper.GetString("SOURCE_HOSTNAME"))
+}
+
+// targetTokenSource returns the credentials used for the target instance.
+func targetTokenSource() oauth2.TokenSource {
+	return configuredTokenSource("TARGET", "")
+}
+
+// issueTokenSource returns the credentials used to comment on and label the triggering issue. A GitLab
+// target token can't be used with GitHub, so the GITHUB_TOKEN of the workflow is used instead.
+func issueTokenSource() oauth2.TokenSource {
+	if TargetType() == "github" {
+		return targetTokenSource()
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return errorTokenSource{err: fmt.Errorf("GITHUB_TOKEN is required to update the triggering issue with a %s target", TargetType())}
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+}
+
+// configuredTokenSource returns a GitHub App installation token source when an app is configured
+// for the given side ("SOURCE" or "TARGET"), and the side's resolved token otherwise.
+// Token sources are cached so installation tokens are shared and only refreshed when they expire.
+func configuredTokenSource(side string, hostname string) oauth2.TokenSource {
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+
+	appID := viper.GetString(side + "_APP_ID")
+	key := side + "|" + hostname + "|" + appID + "|" + viper.GetString(side+"_INSTALLATION_ID") + "|" + viper.GetString(side+"_TOKEN") + "|" + viper.GetString(side+"_TOKEN_FILE")
+	if tokenSource, ok := tokenSources[key]; ok {
+		return tokenSource
+	}
+
+	if appID == "" {
+		token, err := resolveToken(side, hostname)
+		if err != nil {
+			return errorTokenSource{err: err}
+		}
+		tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		tokenSources[key] = tokenSource
+		return tokenSource
+	}
+
+	appSource, err := newAppTokenSource(appID, viper.GetString(side+"_INSTALLATION_ID"), viper.GetString(side+"_PRIVATE_KEY"), hostname)
+	if err != nil {
+		// Surface configuration errors on first use, like any other token error
+		return errorTokenSource{err: fmt.Errorf("invalid %s GitHub App configuration: %v", strings.ToLower(side), err)}
+	}
+
+	// Refresh installation tokens a few minutes before they expire so long transfers don't fail midway
+	tokenSource := oauth2.ReuseTokenSourceWithExpiry(nil, appSource, 5*time.Minute)
+	tokenSources[key] = tokenSource
+
+	return tokenSource
+}
+
+// ghAuthToken returns the token stored by the gh CLI for a hostname.
+var ghAuthToken = func(hostname string) (string, error) {
+	output, err := exec.Command("gh", "auth", "token", "--hostname", hostname).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// resolveToken finds the token of a side ("SOURCE" or "TARGET"), in order from the token flag, the
+// token file, the GH_<SIDE>_PAT environment variable (or GH_PAT for the target, as used by GEI), and
+// finally the gh CLI credentials of the hostname. Explicit flags come first, so an exported GH_PAT
+// doesn't override a token file passed on the command line.
+func resolveToken(side string, hostname string) (string, error) {
+	if token := viper.GetString(side + "_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	if tokenFile := viper.GetString(side + "_TOKEN_FILE"); tokenFile != "" {
+		content, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read %s token file: %v", strings.ToLower(side), err)
+		}
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("%s token file %s is empty", strings.ToLower(side), tokenFile)
+		}
+		return token, nil
+	}
+
+	envVars := []string{"GH_" + side + "_PAT"}
+	if side == "TARGET" {
+		envVars = append(envVars, "GH_PAT")
+	}
+	for _, envVar := range envVars {
+		if token := os.Getenv(envVar); token != "" {
+			return token, nil
+		}
+	}
+
+	if hostname == "" {
+		hostname = "github.com"
+	}
+	if token, err := ghAuthToken(strings.TrimSuffix(hostname, "/")); err == nil && token != "" {
+		return token, nil
+	}
+
+	return "", fmt.Errorf("no %s token found: provide a token flag, the %s environment variable or a token file, or log in with gh auth login --hostname %s",
+		strings.ToLower(side), envVars[0], hostname)
+}
+
+// accessToken returns the current access token of a token source, for raw HTTP requests.
+func accessToken(tokenSource oauth2.TokenSource) (string, error) {
+	token, err := tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+type errorTokenSource struct {
+	err error
+}
+
+func (s errorTokenSource) Token() (*oauth2.Token, error) {
+	return nil, s.err
+}
+
+// appTokenSource mints GitHub App installation tokens.
+type appTokenSource struct {
+	appID          string
+	installationID int64
+	key            *rsa.PrivateKey
+	baseURL        string
+}
+
+func newAppTokenSource(appID string, installationID string, privateKey string, hostname string) (*appTokenSource, error) {
+	if _, err := strconv.ParseInt(appID, 10, 64); err != nil {
+		return nil, fmt.Errorf("app id %q is not a number", appID)
+	}
+	id, err := strconv.ParseInt(installationID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("installation id %q is not a number", installationID)
+	}
+
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := "https://api.github.com"
+	if hostname != "" {
+		baseURL = "https://" + strings.TrimSuffix(hostname, "/") + "/api/v3"
+	}
+
+	return &appTokenSource{appID: appID, installationID: id, key: key, baseURL: baseURL}, nil
+}
+
+// parsePrivateKey parses a PEM private key given either inline or as a file path.
+func parsePrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	content := []byte(privateKey)
+	if !strings.Contains(privateKey, "-----BEGIN") {
+		var err error
+		content, err = os.ReadFile(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read private key: %v", err)
+		}
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+// jwt returns a short-lived JSON Web Token authenticating as the GitHub App.
+func (s *appTokenSource) jwt() (string, error) {
+	now := time.Now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(), // allow for clock drift
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": s.appID,
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token mints a new installation access token.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt()
+	if err != nil {
+		return nil, fmt.Errorf("error signing GitHub App JWT: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.baseURL, s.installationID)
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating installation token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("error creating installation token: HTTP request failed with status code %d", resp.StatusCode)
+	}
+
+	var installationToken struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&installationToken)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding installation token: %v", err)
+	}
+
+	return &oauth2.Token{AccessToken: installationToken.Token, TokenType: "Bearer", Expiry: installationToken.ExpiresAt}, nil
+}
+
+// TokenInfo describes the credentials used for an instance.
+type TokenInfo struct {
+	// Login is the user the token belongs to, empty for GitHub App installation tokens
+	Login string
+	// Scopes are the OAuth scopes of a classic token, nil for fine-grained and installation tokens
+	Scopes []string
+	// RateLimit is the core API rate limit, nil when rate limiting is disabled on the instance
+	RateLimit *github.Rate
+}
+
+// GetSourceTokenInfo validates the source credentials and returns their details.
+func GetSourceTokenInfo() (*TokenInfo, error) {
+	return getTokenInfo(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+}
+
+// GetTargetTokenInfo validates the target credentials and returns their details.
+func GetTargetTokenInfo() (*TokenInfo, error) {
+	return getTokenInfo(targetTokenSource(), "")
+}
+
+func getTokenInfo(tokenSource oauth2.TokenSource, hostname string) (*TokenInfo, error) {
+	client := newGHRestClient(tokenSource, hostname)
+
+	ctx := context.Background()
+	info := &TokenInfo{}
+
+	// The rate limit endpoint works for every kind of token and doesn't count against the limit
+	limits, resp, err := client.RateLimit.Get(ctx)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return nil, err
+	}
+	if limits != nil {
+		info.RateLimit = limits.GetCore()
+	}
+	if resp != nil && resp.Header.Get("X-OAuth-Scopes") != "" {
+		for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+			info.Scopes = append(info.Scopes, strings.TrimSpace(scope))
+		}
+	}
+
+	// Installation tokens can't read the authenticated user
+	user, _, err := client.Users.Get(ctx, "")
+	if err == nil {
+		info.Login = user.GetLogin()
+	}
+
+	return info, nil
+}
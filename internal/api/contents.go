@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// CommitTargetFile creates or updates a file in a target repository through the contents API, on the
+// default branch when branch is empty. It returns the URL of the commit, or an empty string when the
+// file already has the given content and nothing was committed.
+func CommitTargetFile(repository string, path string, branch string, message string, content []byte) (string, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+	owner := viper.GetString("TARGET_ORGANIZATION")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	existing, _, resp, err := client.Repositories.GetContents(ctx, owner, repository, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return "", err
+	}
+
+	options := &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: content,
+	}
+	if branch != "" {
+		options.Branch = github.String(branch)
+	}
+
+	var commit *github.RepositoryContentResponse
+	if existing == nil {
+		commit, _, err = client.Repositories.CreateFile(ctx, owner, repository, path, options)
+	} else {
+		if current, decodeErr := existing.GetContent(); decodeErr == nil && current == string(content) {
+			return "", nil
+		}
+		options.SHA = github.String(existing.GetSHA())
+		commit, _, err = client.Repositories.UpdateFile(ctx, owner, repository, path, options)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return commit.Commit.GetHTMLURL(), nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// GetSourceReleaseDiscussionCategories returns the category of the discussions linked to source releases, as
+// listed by GetSourceRepositoryReleasesWithDiscussions, keyed by release id.
+func GetSourceReleaseDiscussionCategories(owner string, repository string, discussions map[int64]int) (map[int64]string, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	query := `query($owner: String!, $name: String!, $number: Int!) {
+		repository(owner: $owner, name: $name) {
+			discussion(number: $number) {
+				category { name }
+			}
+		}
+	}`
+
+	categories := make(map[int64]string)
+	for id, number := range discussions {
+		var result struct {
+			Repository struct {
+				Discussion *struct {
+					Category struct {
+						Name string `json:"name"`
+					} `json:"category"`
+				} `json:"discussion"`
+			} `json:"repository"`
+		}
+		err := queryGraphQL(ctx, client, query, map[string]interface{}{"owner": owner, "name": repository, "number": number}, &result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get discussion #%d: %v", number, err)
+		}
+		if result.Repository.Discussion != nil {
+			categories[id] = result.Repository.Discussion.Category.Name
+		}
+	}
+
+	return categories, nil
+}
+
+// GetTargetDiscussionCategories returns the discussion category names of a target organization repository,
+// or nil if discussions are disabled.
+func GetTargetDiscussionCategories(repository string) ([]string, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	query := `query($owner: String!, $name: String!) {
+		repository(owner: $owner, name: $name) {
+			hasDiscussionsEnabled
+			discussionCategories(first: 100) {
+				nodes { name }
+			}
+		}
+	}`
+
+	var result struct {
+		Repository struct {
+			HasDiscussionsEnabled bool `json:"hasDiscussionsEnabled"`
+			DiscussionCategories  struct {
+				Nodes []struct {
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	err := queryGraphQL(ctx, client, query, map[string]interface{}{"owner": viper.GetString("TARGET_ORGANIZATION"), "name": repository}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get target discussion categories: %w", err)
+	}
+	if !result.Repository.HasDiscussionsEnabled {
+		return nil, nil
+	}
+
+	var categories []string
+	for _, category := range result.Repository.DiscussionCategories.Nodes {
+		categories = append(categories, category.Name)
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mona-actions/gh-migrate-releases/internal/files"
+	"github.com/mona-actions/gh-migrate-releases/internal/progress"
+	"github.com/spf13/viper"
+)
+
+// gitlabPackageName is the generic package release assets are uploaded to, with the release tag as version.
+const gitlabPackageName = "release-assets"
+
+// gitlabVersionInvalid matches the characters generic package versions can't contain.
+var gitlabVersionInvalid = regexp.MustCompile(`[^0-9A-Za-z.+_-]+`)
+
+// gitlabTarget syncs releases to the projects of a GitLab group, with assets uploaded to the generic
+// package registry of the project and linked from the release.
+type gitlabTarget struct {
+	baseURL string
+	group   string
+	token   string
+}
+
+// gitlabRelease is a release as returned by the GitLab Releases API.
+type gitlabRelease struct {
+	TagName     string     `json:"tag_name"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ReleasedAt  *time.Time `json:"released_at"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// newGitLabTarget creates the GitLab target of a group on a hostname, gitlab.com when empty. The hostname
+// may include a scheme, https being used otherwise.
+func newGitLabTarget(hostname string, group string) (*gitlabTarget, error) {
+	if hostname == "" {
+		hostname = "gitlab.com"
+	}
+	if !strings.Contains(hostname, "://") {
+		hostname = "https://" + hostname
+	}
+
+	token, err := gitlabToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitlabTarget{baseURL: strings.TrimSuffix(hostname, "/") + "/api/v4", group: group, token: token}, nil
+}
+
+// gitlabToken finds the GitLab token, in order from the target token flag, the target token file
+// and the GITLAB_TOKEN environment variable, like resolveToken does for GitHub tokens.
+func gitlabToken() (string, error) {
+	if token := viper.GetString("TARGET_TOKEN"); token != "" {
+		return token, nil
+	}
+	if tokenFile := viper.GetString("TARGET_TOKEN_FILE"); tokenFile != "" {
+		content, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read target token file: %v", err)
+		}
+		if token := strings.TrimSpace(string(content)); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("target token file %s is empty", tokenFile)
+	}
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	return "", errors.New("no GitLab token found: provide the target token flag, a target token file or the GITLAB_TOKEN environment variable")
+}
+
+// projectPath returns the API path of a project of the group.
+func (t *gitlabTarget) projectPath(repository string) string {
+	return "/projects/" + gitlabEscape(t.group+"/"+repository)
+}
+
+// gitlabEscape escapes a path parameter, including slashes, as GitLab expects for project paths and tags.
+func gitlabEscape(value string) string {
+	return strings.ReplaceAll(url.PathEscape(value), "/", "%2F")
+}
+
+func (t *gitlabTarget) RepositoryExists(repository string) (bool, error) {
+	resp, err := t.do("GET", t.projectPath(repository), nil, "")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, gitlabError(resp, "unable to get target project")
+	}
+}
+
+func (t *gitlabTarget) ListReleases(repository string) ([]*github.RepositoryRelease, error) {
+	var allReleases []*github.RepositoryRelease
+
+	page := "1"
+	for page != "" {
+		resp, err := t.do("GET", t.projectPath(repository)+"/releases?per_page=100&page="+page, nil, "")
+		if err != nil {
+			return allReleases, fmt.Errorf("unable to get target releases: %w", err)
+		}
+
+		var releases []gitlabRelease
+		err = decodeGitLabResponse(resp, http.StatusOK, "unable to get target releases", &releases)
+		if err != nil {
+			return allReleases, err
+		}
+		for _, release := range releases {
+			allReleases = append(allReleases, release.toGitHub())
+		}
+
+		page = resp.Header.Get("X-Next-Page")
+	}
+
+	return allReleases, nil
+}
+
+func (t *gitlabTarget) CreateRelease(repository string, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	if release.GetDraft() {
+		return nil, errors.New("GitLab has no draft releases, the draft release was not created")
+	}
+
+	request := map[string]string{
+		"tag_name":    release.GetTagName(),
+		"name":        release.GetName(),
+		"description": release.GetBody(),
+	}
+	// The ref is only used when the tag doesn't exist in the project yet
+	if release.GetTargetCommitish() != "" {
+		request["ref"] = release.GetTargetCommitish()
+	}
+	// Keep the source release date, GitLab would use the current time otherwise
+	if release.PublishedAt != nil {
+		request["released_at"] = release.GetPublishedAt().Format(time.RFC3339)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.do("POST", t.projectPath(repository)+"/releases", bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var created gitlabRelease
+	err = decodeGitLabResponse(resp, http.StatusCreated, "unable to create release", &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created.toGitHub(), nil
+}
+
+func (t *gitlabTarget) UploadAsset(repository string, release *github.RepositoryRelease, asset *github.ReleaseAsset) error {
+	fileName := tmpDir + "/" + asset.GetName()
+
+	file, err := files.OpenFile(fileName)
+	if err != nil {
+		return fmt.Errorf("error opening file: %v err: %v", fileName, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error getting file size of %v err: %v ", fileName, err)
+	}
+
+	limiter, err := transferLimiter("MAX_UPLOAD_RATE")
+	if err != nil {
+		return err
+	}
+
+	// Upload the asset to the generic package of the release
+	packagePath := t.projectPath(repository) + "/packages/generic/" + gitlabPackageName + "/" + url.PathEscape(gitlabPackageVersion(release.GetTagName())) + "/" + gitlabEscape(asset.GetName())
+
+	transfer := progress.StartTransfer("Uploading", asset.GetName(), stat.Size())
+	resp, err := t.doWithLength("PUT", packagePath, transfer.Reader(throttle(file, limiter)), "application/octet-stream", stat.Size())
+	if err != nil {
+		transfer.Finish(err)
+		return fmt.Errorf("error uploading asset %s: %w", asset.GetName(), err)
+	}
+	err = decodeGitLabResponse(resp, http.StatusCreated, "error uploading asset "+asset.GetName(), nil)
+	transfer.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	// Link the package file from the release
+	link, err := json.Marshal(map[string]string{
+		"name":      asset.GetName(),
+		"url":       t.baseURL + packagePath,
+		"link_type": "package",
+	})
+	if err != nil {
+		return err
+	}
+	resp, err = t.do("POST", t.projectPath(repository)+"/releases/"+gitlabEscape(release.GetTagName())+"/assets/links", bytes.NewReader(link), "application/json")
+	if err != nil {
+		return fmt.Errorf("error linking asset %s: %w", asset.GetName(), err)
+	}
+	err = decodeGitLabResponse(resp, http.StatusCreated, "error linking asset "+asset.GetName(), nil)
+	if err != nil {
+		return err
+	}
+
+	file.Close()
+	err = files.RemoveFile(fileName)
+	if err != nil {
+		return fmt.Errorf("error deleting asset from local storage: %v err: %v", asset.GetName(), err)
+	}
+
+	return nil
+}
+
+// gitlabPackageVersion returns the generic package version of a release tag. Tags with characters versions
+// can't contain are suffixed with a hash of the tag, so tags such as v1.0/rc and v1.0-rc don't share a package.
+func gitlabPackageVersion(tag string) string {
+	version := strings.Trim(gitlabVersionInvalid.ReplaceAllString(tag, "-"), ".-")
+	if version == tag {
+		return version
+	}
+
+	hash := sha256.Sum256([]byte(tag))
+	return strings.TrimPrefix(version+"-"+hex.EncodeToString(hash[:4]), "-")
+}
+
+func (t *gitlabTarget) do(method string, path string, body io.Reader, contentType string) (*http.Response, error) {
+	return t.doWithLength(method, path, body, contentType, -1)
+}
+
+func (t *gitlabTarget) doWithLength(method string, path string, body io.Reader, contentType string, length int64) (*http.Response, error) {
+	req, err := http.NewRequest(method, t.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if length >= 0 {
+		req.ContentLength = length
+	}
+	req.Header.Set("PRIVATE-TOKEN", t.token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return httpClient.Do(req)
+}
+
+// decodeGitLabResponse checks the status code of a response and decodes its body into value, unless value is nil.
+func decodeGitLabResponse(resp *http.Response, expectedStatus int, message string, value interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return gitlabError(resp, message)
+	}
+	if value == nil {
+		return nil
+	}
+
+	err := json.NewDecoder(resp.Body).Decode(value)
+	if err != nil {
+		return fmt.Errorf("%s: error decoding response: %v", message, err)
+	}
+	return nil
+}
+
+// gitlabError builds the error of an unexpected GitLab response, including the message GitLab gives.
+func gitlabError(resp *http.Response, message string) error {
+	var body struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	if json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body) == nil {
+		if body.Message != nil {
+			message = fmt.Sprintf("%s (%v)", message, body.Message)
+		} else if body.Error != "" {
+			message = fmt.Sprintf("%s (%s)", message, body.Error)
+		}
+	}
+
+	return &RequestError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-Id"), Message: message}
+}
+
+// toGitHub maps a GitLab release to a GitHub release, with release links as assets.
+func (r gitlabRelease) toGitHub() *github.RepositoryRelease {
+	release := &github.RepositoryRelease{
+		TagName: github.String(r.TagName),
+		Name:    github.String(r.Name),
+		Body:    github.String(r.Description),
+		HTMLURL: github.String(r.Links.Self),
+	}
+	if r.CreatedAt != nil {
+		release.CreatedAt = &github.Timestamp{Time: *r.CreatedAt}
+	}
+	if r.ReleasedAt != nil {
+		release.PublishedAt = &github.Timestamp{Time: *r.ReleasedAt}
+	}
+	for _, link := range r.Assets.Links {
+		release.Assets = append(release.Assets, &github.ReleaseAsset{
+			ID:                 github.Int64(link.ID),
+			Name:               github.String(link.Name),
+			BrowserDownloadURL: github.String(link.URL),
+		})
+	}
+
+	return release
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// fakeGitLab is an httptest stand-in for the GitLab API of a "target-group/repo" project.
+type fakeGitLab struct {
+	server   *httptest.Server
+	releases []map[string]interface{}
+	packages map[string]string
+	links    []map[string]string
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	t.Helper()
+
+	gitlab := &fakeGitLab{packages: make(map[string]string)}
+	const project = "/api/v4/projects/target-group%2Frepo"
+	gitlab.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "gitlab-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+
+		switch path := r.URL.EscapedPath(); {
+		case r.Method == "GET" && path == project:
+			w.Write([]byte(`{"id": 1}`))
+		case r.Method == "GET" && path == project+"/releases":
+			// One release per page, to exercise pagination
+			page := 1
+			if r.URL.Query().Get("page") == "2" {
+				page = 2
+			}
+			if page < len(gitlab.releases) {
+				w.Header().Set("X-Next-Page", "2")
+			}
+			json.NewEncoder(w).Encode(gitlab.releases[page-1 : page])
+		case r.Method == "POST" && path == project+"/releases":
+			var request map[string]string
+			json.NewDecoder(r.Body).Decode(&request)
+			if request["tag_name"] == "v0.9.0" {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"message": "Release already exists"}`))
+				return
+			}
+			release := map[string]interface{}{
+				"tag_name":    request["tag_name"],
+				"name":        request["name"],
+				"description": request["description"],
+				"_links":      map[string]string{"self": "https://gitlab.example.com/target-group/repo/-/releases/" + request["tag_name"]},
+			}
+			if request["released_at"] != "" {
+				release["released_at"] = request["released_at"]
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(release)
+		case r.Method == "PUT" && path == project+"/packages/generic/release-assets/release-1.0-05c07dbf/app%20build.zip":
+			content, _ := io.ReadAll(r.Body)
+			gitlab.packages[path] = string(content)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"message": "201 Created"}`))
+		case r.Method == "POST" && path == project+"/releases/release%2F1.0/assets/links":
+			var link map[string]string
+			json.NewDecoder(r.Body).Decode(&link)
+			gitlab.links = append(gitlab.links, link)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Not Found"}`))
+		}
+	}))
+	t.Cleanup(gitlab.server.Close)
+
+	return gitlab
+}
+
+func newTestGitLabTarget(t *testing.T, gitlab *fakeGitLab) *gitlabTarget {
+	t.Helper()
+
+	t.Setenv("GITLAB_TOKEN", "gitlab-token")
+	target, err := newGitLabTarget(gitlab.server.URL, "target-group")
+	if err != nil {
+		t.Fatalf("newGitLabTarget returned an error: %v", err)
+	}
+	return target
+}
+
+func TestGitLabRepositoryExists(t *testing.T) {
+	target := newTestGitLabTarget(t, newFakeGitLab(t))
+
+	exists, err := target.RepositoryExists("repo")
+	if err != nil || !exists {
+		t.Errorf("RepositoryExists(repo) = %v, %v, expected true", exists, err)
+	}
+
+	exists, err = target.RepositoryExists("missing")
+	if err != nil || exists {
+		t.Errorf("RepositoryExists(missing) = %v, %v, expected false", exists, err)
+	}
+}
+
+func TestGitLabListReleases(t *testing.T) {
+	gitlab := newFakeGitLab(t)
+	gitlab.releases = []map[string]interface{}{
+		{"tag_name": "v2.0.0", "name": "Version 2", "description": "body", "released_at": "2024-02-01T00:00:00Z"},
+		{"tag_name": "v1.0.0", "name": "Version 1", "assets": map[string]interface{}{
+			"links": []map[string]interface{}{{"id": 7, "name": "app.zip", "url": "https://gitlab.example.com/app.zip"}},
+		}},
+	}
+	target := newTestGitLabTarget(t, gitlab)
+
+	releases, err := target.ListReleases("repo")
+	if err != nil {
+		t.Fatalf("ListReleases returned an error: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("ListReleases returned %d releases, expected 2", len(releases))
+	}
+	if releases[0].GetTagName() != "v2.0.0" || releases[0].GetBody() != "body" || releases[0].PublishedAt == nil {
+		t.Errorf("First release = %+v", releases[0])
+	}
+	if len(releases[1].Assets) != 1 || releases[1].Assets[0].GetName() != "app.zip" {
+		t.Errorf("Second release assets = %+v, expected the app.zip link", releases[1].Assets)
+	}
+}
+
+func TestGitLabCreateRelease(t *testing.T) {
+	target := newTestGitLabTarget(t, newFakeGitLab(t))
+
+	created, err := target.CreateRelease("repo", &github.RepositoryRelease{
+		TagName:         github.String("release/1.0"),
+		Name:            github.String("Release 1.0"),
+		Body:            github.String("notes"),
+		TargetCommitish: github.String("main"),
+		PublishedAt:     &github.Timestamp{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("CreateRelease returned an error: %v", err)
+	}
+	if created.GetTagName() != "release/1.0" || created.GetBody() != "notes" || created.GetHTMLURL() == "" {
+		t.Errorf("CreateRelease returned %+v", created)
+	}
+	if !created.GetPublishedAt().Time.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreateRelease released_at = %v, expected the source publication date", created.GetPublishedAt())
+	}
+
+	_, err = target.CreateRelease("repo", &github.RepositoryRelease{TagName: github.String("v0.9.0")})
+	if err == nil || err.Error() != "unable to create release (Release already exists): HTTP request failed with status code 409" {
+		t.Errorf("CreateRelease of an existing release returned %v", err)
+	}
+
+	_, err = target.CreateRelease("repo", &github.RepositoryRelease{TagName: github.String("v3.0.0"), Draft: github.Bool(true)})
+	if err == nil {
+		t.Errorf("CreateRelease of a draft did not return an error")
+	}
+}
+
+func TestGitLabUploadAsset(t *testing.T) {
+	gitlab := newFakeGitLab(t)
+	target := newTestGitLabTarget(t, gitlab)
+
+	previousTmpDir := tmpDir
+	tmpDir = t.TempDir()
+	t.Cleanup(func() { tmpDir = previousTmpDir })
+
+	fileName := filepath.Join(tmpDir, "app build.zip")
+	os.WriteFile(fileName, []byte("asset content"), 0644)
+
+	release := &github.RepositoryRelease{TagName: github.String("release/1.0")}
+	err := target.UploadAsset("repo", release, &github.ReleaseAsset{Name: github.String("app build.zip")})
+	if err != nil {
+		t.Fatalf("UploadAsset returned an error: %v", err)
+	}
+
+	packagePath := "/api/v4/projects/target-group%2Frepo/packages/generic/release-assets/release-1.0-05c07dbf/app%20build.zip"
+	if gitlab.packages[packagePath] != "asset content" {
+		t.Errorf("Uploaded packages = %v", gitlab.packages)
+	}
+	if len(gitlab.links) != 1 || gitlab.links[0]["name"] != "app build.zip" || gitlab.links[0]["url"] != gitlab.server.URL+packagePath {
+		t.Errorf("Release links = %v", gitlab.links)
+	}
+	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+		t.Errorf("UploadAsset did not remove the local copy of the asset")
+	}
+}
+
+func TestGitLabPackageVersion(t *testing.T) {
+	tests := map[string]string{
+		"v1.0.0":  "v1.0.0",
+		"v1.0-rc": "v1.0-rc",
+		"v1.0/rc": "v1.0-rc-50f9fed1",
+		"/":       "8a5edab2",
+	}
+	for tag, expected := range tests {
+		if version := gitlabPackageVersion(tag); version != expected {
+			t.Errorf("gitlabPackageVersion(%q) = %q, expected %q", tag, version, expected)
+		}
+	}
+}
+
+func TestGitLabToken(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+	if _, err := newGitLabTarget("gitlab.example.com", "target-group"); err == nil {
+		t.Errorf("newGitLabTarget without a token did not return an error")
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// queryGraphQL runs a GraphQL query with the REST client and decodes its data into result.
+func queryGraphQL(ctx context.Context, client *github.Client, query string, variables map[string]interface{}, result interface{}) error {
+	// The GraphQL endpoint lives next to the REST API: /graphql on github.com, /api/graphql on GHES
+	endpoint := "graphql"
+	if strings.HasSuffix(client.BaseURL.Path, "/api/v3/") {
+		endpoint = "../graphql"
+	}
+
+	req, err := client.NewRequest("POST", endpoint, map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	_, err = client.Do(ctx, req, &resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		var messages []string
+		for _, graphQLError := range resp.Errors {
+			messages = append(messages, graphQLError.Message)
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+
+	return json.Unmarshal(resp.Data, result)
+}
+
+// TargetIdentity is a member of the target organization along with the identities it can be matched on.
+type TargetIdentity struct {
+	Login      string
+	Email      string
+	SAMLNameID string
+	SAMLEmails []string
+}
+
+// GetTargetOrganizationIdentities returns the members of the target organization with their public email,
+// merged with the organization's SAML external identities when SAML single sign-on is configured.
+// When the SAML identities can't be read, e.g. without admin:org or with SAML configured for the
+// enterprise, the members are returned along with the error.
+func GetTargetOrganizationIdentities(organization string) ([]TargetIdentity, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	identities := make(map[string]*TargetIdentity)
+	var logins []string
+	identity := func(login string) *TargetIdentity {
+		if identities[login] == nil {
+			identities[login] = &TargetIdentity{Login: login}
+			logins = append(logins, login)
+		}
+		return identities[login]
+	}
+	collect := func() []TargetIdentity {
+		var result []TargetIdentity
+		for _, login := range logins {
+			result = append(result, *identities[login])
+		}
+		return result
+	}
+
+	membersQuery := `query($organization: String!, $cursor: String) {
+		organization(login: $organization) {
+			membersWithRole(first: 100, after: $cursor) {
+				pageInfo { hasNextPage endCursor }
+				nodes { login email }
+			}
+		}
+	}`
+
+	var cursor *string
+	for {
+		var result struct {
+			Organization struct {
+				MembersWithRole struct {
+					PageInfo pageInfo
+					Nodes    []struct {
+						Login string
+						Email string
+					}
+				}
+			}
+		}
+		err := queryGraphQL(ctx, client, membersQuery, map[string]interface{}{"organization": organization, "cursor": cursor}, &result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get organization members: %w", err)
+		}
+
+		for _, member := range result.Organization.MembersWithRole.Nodes {
+			identity(member.Login).Email = member.Email
+		}
+
+		if !result.Organization.MembersWithRole.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &result.Organization.MembersWithRole.PageInfo.EndCursor
+	}
+
+	samlQuery := `query($organization: String!, $cursor: String) {
+		organization(login: $organization) {
+			samlIdentityProvider {
+				externalIdentities(first: 100, after: $cursor) {
+					pageInfo { hasNextPage endCursor }
+					nodes {
+						samlIdentity { nameId emails { value } }
+						user { login }
+					}
+				}
+			}
+		}
+	}`
+
+	cursor = nil
+	for {
+		var result struct {
+			Organization struct {
+				SamlIdentityProvider *struct {
+					ExternalIdentities struct {
+						PageInfo pageInfo
+						Nodes    []struct {
+							SamlIdentity struct {
+								NameID string `json:"nameId"`
+								Emails []struct {
+									Value string
+								}
+							}
+							User *struct {
+								Login string
+							}
+						}
+					}
+				}
+			}
+		}
+		err := queryGraphQL(ctx, client, samlQuery, map[string]interface{}{"organization": organization, "cursor": cursor}, &result)
+		if err != nil {
+			return collect(), fmt.Errorf("unable to get organization SAML identities: %w", err)
+		}
+
+		// Organizations without SAML single sign-on have no identity provider
+		provider := result.Organization.SamlIdentityProvider
+		if provider == nil {
+			break
+		}
+
+		for _, externalIdentity := range provider.ExternalIdentities.Nodes {
+			if externalIdentity.User == nil {
+				continue // identity not linked to a user yet
+			}
+			member := identity(externalIdentity.User.Login)
+			member.SAMLNameID = externalIdentity.SamlIdentity.NameID
+			for _, email := range externalIdentity.SamlIdentity.Emails {
+				member.SAMLEmails = append(member.SAMLEmails, email.Value)
+			}
+		}
+
+		if !provider.ExternalIdentities.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &provider.ExternalIdentities.PageInfo.EndCursor
+	}
+
+	return collect(), nil
+}
+
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   string
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// CreateIssueComment comments on an issue and returns the id of the comment, so it can be updated.
+func CreateIssueComment(owner string, repository string, issueNumber int, body string) (int64, error) {
+	client := newGHRestClient(issueTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	comment, _, err := client.Issues.CreateComment(ctx, owner, repository, issueNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		return 0, err
+	}
+
+	return comment.GetID(), nil
+}
+
+// UpdateIssueComment replaces the body of an issue comment.
+func UpdateIssueComment(owner string, repository string, commentID int64, body string) error {
+	client := newGHRestClient(issueTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	_, _, err := client.Issues.EditComment(ctx, owner, repository, commentID, &github.IssueComment{Body: &body})
+
+	return err
+}
+
+// AddIssueLabels adds labels to an issue, creating labels that don't exist in the repository yet.
+func AddIssueLabels(owner string, repository string, issueNumber int, labels []string) error {
+	client := newGHRestClient(issueTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	_, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repository, issueNumber, labels)
+
+	return err
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// requestIDHeader identifies a request to GitHub support.
+const requestIDHeader = "X-GitHub-Request-Id"
+
+// httpClient is used for the raw asset downloads and uploads.
+var httpClient = &http.Client{Transport: loggingTransport{next: http.DefaultTransport}}
+
+// loggingTransport logs every request to GitHub at debug level, along with its request id.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	// Query strings are left out as download redirects carry tokens in them
+	url := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		slog.Debug("GitHub request failed", "method", req.Method, "url", url, "error", err, "duration", time.Since(start))
+		return resp, err
+	}
+
+	slog.Debug("GitHub request", "method", req.Method, "url", url, "status", resp.StatusCode,
+		"request_id", resp.Header.Get(requestIDHeader), "duration", time.Since(start))
+
+	return resp, nil
+}
+
+// RequestError is an unexpected response to a raw request to GitHub.
+type RequestError struct {
+	StatusCode int
+	RequestID  string
+	Message    string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: HTTP request failed with status code %d", e.Message, e.StatusCode)
+}
+
+func newRequestError(resp *http.Response, message string) *RequestError {
+	return &RequestError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get(requestIDHeader), Message: message}
+}
+
+// ErrorAttrs returns the attributes logged for an error, including the GitHub request id of the
+// failed request when there is one.
+func ErrorAttrs(err error) []any {
+	attrs := []any{"error", err}
+	if requestID := RequestID(err); requestID != "" {
+		attrs = append(attrs, "request_id", requestID)
+	}
+	return attrs
+}
+
+// RequestID returns the GitHub request id of the response an error was caused by, or "" if there is none.
+func RequestID(err error) string {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.RequestID
+	}
+
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		return githubErr.Response.Header.Get(requestIDHeader)
+	}
+
+	return ""
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// GetTargetRepositoryReleases returns every release of a target organization repository, drafts included.
+func GetTargetRepositoryReleases(repository string) ([]*github.RepositoryRelease, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	var allReleases []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		releases, resp, err := client.Repositories.ListReleases(ctx, viper.GetString("TARGET_ORGANIZATION"), repository, opts)
+		if err != nil {
+			return allReleases, fmt.Errorf("unable to get target releases: %w", err)
+		}
+		allReleases = append(allReleases, releases...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allReleases, nil
+}
+
+// GetSourceLatestReleaseTag returns the tag of the release marked as latest in a source repository, or "" if there is none.
+func GetSourceLatestReleaseTag(owner string, repository string) (string, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+	return getLatestReleaseTag(client, owner, repository)
+}
+
+// GetTargetLatestReleaseTag returns the tag of the release marked as latest in a target repository, or "" if there is none.
+func GetTargetLatestReleaseTag(repository string) (string, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+	return getLatestReleaseTag(client, viper.GetString("TARGET_ORGANIZATION"), repository)
+}
+
+func getLatestReleaseTag(client *github.Client, owner string, repository string) (string, error) {
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	release, resp, err := client.Repositories.GetLatestRelease(ctx, owner, repository)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to get latest release: %w", err)
+	}
+
+	return release.GetTagName(), nil
+}
+
+// GetSourceTagCommitSHA returns the commit a tag of a source repository points to, or "" if the tag doesn't exist.
+func GetSourceTagCommitSHA(owner string, repository string, tag string) (string, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+	return getTagCommitSHA(client, owner, repository, tag)
+}
+
+// GetTargetTagCommitSHA returns the commit a tag of a target repository points to, or "" if the tag doesn't exist.
+func GetTargetTagCommitSHA(repository string, tag string) (string, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+	return getTagCommitSHA(client, viper.GetString("TARGET_ORGANIZATION"), repository, tag)
+}
+
+func getTagCommitSHA(client *github.Client, owner string, repository string, tag string) (string, error) {
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	ref, resp, err := client.Git.GetRef(ctx, owner, repository, "tags/"+tag)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to get tag %s: %w", tag, err)
+	}
+
+	// Peel annotated tags to the commit they point to
+	object := ref.GetObject()
+	for object.GetType() == "tag" {
+		tagObject, _, err := client.Git.GetTag(ctx, owner, repository, object.GetSHA())
+		if err != nil {
+			return "", fmt.Errorf("unable to get tag %s: %w", tag, err)
+		}
+		object = tagObject.GetObject()
+	}
+
+	return object.GetSHA(), nil
+}
+
+// DeleteTargetRelease deletes a release of a target organization repository. The tag of the release is kept.
+func DeleteTargetRelease(repository string, id int64) error {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	_, err := client.Repositories.DeleteRelease(ctx, viper.GetString("TARGET_ORGANIZATION"), repository, id)
+	if err != nil {
+		return fmt.Errorf("unable to delete release: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTargetTag deletes a tag of a target organization repository. Tags that don't exist are ignored.
+// TargetTagExists returns whether a tag exists in a target organization repository.
+func TargetTagExists(repository string, tag string) (bool, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	_, resp, err := client.Git.GetRef(ctx, viper.GetString("TARGET_ORGANIZATION"), repository, "tags/"+tag)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to get tag %s: %w", tag, err)
+	}
+
+	return true, nil
+}
+
+func DeleteTargetTag(repository string, tag string) error {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	resp, err := client.Git.DeleteRef(ctx, viper.GetString("TARGET_ORGANIZATION"), repository, "tags/"+tag)
+	if err != nil {
+		// A missing ref is reported as 422 rather than 404
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+			return nil
+		}
+		return fmt.Errorf("unable to delete tag %s: %w", tag, err)
+	}
+
+	return nil
+}
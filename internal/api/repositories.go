@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// GetSourceRepository returns a repository of the source instance, or nil if it does not exist or isn't visible to the source token.
+func GetSourceRepository(owner string, repository string) (*github.Repository, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	repo, resp, err := client.Repositories.Get(ctx, owner, repository)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// GetTargetRepository returns a repository of the target organization, or nil if it does not exist or isn't visible to the target token.
+func GetTargetRepository(repository string) (*github.Repository, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	repo, resp, err := client.Repositories.Get(ctx, viper.GetString("TARGET_ORGANIZATION"), repository)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// GetSourceOrganizationRepositories returns every repository of a source organization.
+func GetSourceOrganizationRepositories(organization string) ([]*github.Repository, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+
+	var allRepositories []*github.Repository
+	opts := &github.RepositoryListByOrgOptions{Type: "all", ListOptions: github.ListOptions{PerPage: 100}}
+
+	for {
+		repos, resp, err := client.Repositories.ListByOrg(ctx, organization, opts)
+		if err != nil {
+			return allRepositories, fmt.Errorf("unable to get repositories: %w", err)
+		}
+		allRepositories = append(allRepositories, repos...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allRepositories, nil
+}
+
+// HasSourceReleases returns whether a source repository has at least one release.
+func HasSourceReleases(owner string, repository string) (bool, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	releases, _, err := client.Repositories.ListReleases(ctx, owner, repository, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return false, fmt.Errorf("unable to get releases: %w", err)
+	}
+
+	return len(releases) > 0, nil
+}
+
+// SetTargetRepositoryArchived archives or unarchives a target organization repository.
+func SetTargetRepositoryArchived(repository string, archived bool) error {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	_, _, err := client.Repositories.Edit(ctx, viper.GetString("TARGET_ORGANIZATION"), repository, &github.Repository{Archived: github.Bool(archived)})
+	if err != nil {
+		return fmt.Errorf("unable to set archived to %t: %v", archived, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// Target is the platform releases are synced to. Releases are exchanged as GitHub releases, which
+// other platforms map their releases to and from.
+type Target interface {
+	// RepositoryExists reports whether a repository of the target organization exists and is visible to the target token.
+	RepositoryExists(repository string) (bool, error)
+	// ListReleases returns every release of a target repository.
+	ListReleases(repository string) ([]*github.RepositoryRelease, error)
+	// CreateRelease creates a release from a create request, as built by releases.NewCreateRequest.
+	CreateRelease(repository string, release *github.RepositoryRelease) (*github.RepositoryRelease, error)
+	// UploadAsset uploads an asset downloaded with DownloadReleaseAssets to a target release, and removes the local copy.
+	UploadAsset(repository string, release *github.RepositoryRelease, asset *github.ReleaseAsset) error
+}
+
+// TargetType returns the configured target platform, github or gitlab.
+func TargetType() string {
+	if targetType := strings.ToLower(viper.GetString("TARGET_TYPE")); targetType != "" {
+		return targetType
+	}
+	return "github"
+}
+
+// NewTarget returns the configured target platform.
+func NewTarget() (Target, error) {
+	switch TargetType() {
+	case "github":
+		return githubTarget{}, nil
+	case "gitlab":
+		return newGitLabTarget(viper.GetString("TARGET_HOSTNAME"), viper.GetString("TARGET_ORGANIZATION"))
+	default:
+		return nil, fmt.Errorf("invalid target type %q, expected github or gitlab", viper.GetString("TARGET_TYPE"))
+	}
+}
+
+// githubTarget syncs releases to the target organization on github.com.
+type githubTarget struct{}
+
+func (githubTarget) RepositoryExists(repository string) (bool, error) {
+	targetRepository, err := GetTargetRepository(repository)
+	return targetRepository != nil, err
+}
+
+func (githubTarget) ListReleases(repository string) ([]*github.RepositoryRelease, error) {
+	return GetTargetRepositoryReleases(repository)
+}
+
+func (githubTarget) CreateRelease(repository string, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	return CreateRelease(repository, release)
+}
+
+func (githubTarget) UploadAsset(repository string, release *github.RepositoryRelease, asset *github.ReleaseAsset) error {
+	return UploadAssetViaURL(release.GetUploadURL(), asset)
+}
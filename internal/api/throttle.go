@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*rate.Limiter)
+
+	ratePattern = regexp.MustCompile(`^(?i)\s*(\d+(?:\.\d+)?)\s*(b|k|kb|kib|m|mb|mib|g|gb|gib)?\s*(?:/s)?\s*$`)
+	rateUnits   = map[string]float64{
+		"": 1, "b": 1,
+		"k": 1e3, "kb": 1e3, "kib": 1 << 10,
+		"m": 1e6, "mb": 1e6, "mib": 1 << 20,
+		"g": 1e9, "gb": 1e9, "gib": 1 << 30,
+	}
+)
+
+// ParseRate parses a transfer rate in bytes per second, such as "500KB", "10MiB/s" or "1.5G".
+// KB, MB and GB are powers of 1000 and KiB, MiB and GiB powers of 1024. An empty rate means unlimited and is returned as 0.
+func ParseRate(value string) (int64, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	match := ratePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid rate %q, expected a number of bytes per second such as 500KB or 10MiB", value)
+	}
+	number, _ := strconv.ParseFloat(match[1], 64)
+	bytesPerSecond := int64(number * rateUnits[strings.ToLower(match[2])])
+	if bytesPerSecond <= 0 {
+		return 0, fmt.Errorf("invalid rate %q, must be greater than 0", value)
+	}
+
+	return bytesPerSecond, nil
+}
+
+// transferLimiter returns the limiter shared by every transfer limited by the given rate setting,
+// or nil when no rate is set.
+func transferLimiter(setting string) (*rate.Limiter, error) {
+	value := viper.GetString(setting)
+	bytesPerSecond, err := ParseRate(value)
+	if err != nil || bytesPerSecond == 0 {
+		return nil, err
+	}
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	key := setting + "|" + value
+	if limiter, ok := limiters[key]; ok {
+		return limiter, nil
+	}
+
+	// Allow bursts of up to a second of transfer, so reads are never larger than the bucket
+	limiter := rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+	limiters[key] = limiter
+
+	return limiter, nil
+}
+
+// throttledReader limits the rate at which a reader can be read.
+type throttledReader struct {
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+// throttle returns a reader limited by the limiter, or the reader itself when limiter is nil.
+func throttle(reader io.Reader, limiter *rate.Limiter) io.Reader {
+	if limiter == nil {
+		return reader
+	}
+	return &throttledReader{reader: reader, limiter: limiter}
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(context.Background(), n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
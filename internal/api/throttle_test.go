@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+	}{
+		{"", 0},
+		{"2048", 2048},
+		{"500KB", 500000},
+		{"500k", 500000},
+		{"10MiB/s", 10 << 20},
+		{"1.5G", 1500000000},
+		{" 1 mb ", 1000000},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseRate(test.value)
+		if err != nil {
+			t.Errorf("ParseRate(%q) returned an error: %v", test.value, err)
+		} else if actual != test.expected {
+			t.Errorf("ParseRate(%q) = %d, expected %d", test.value, actual, test.expected)
+		}
+	}
+
+	for _, value := range []string{"fast", "10TB", "-1MB", "0"} {
+		if _, err := ParseRate(value); err == nil {
+			t.Errorf("ParseRate(%q) did not return an error", value)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	content := bytes.Repeat([]byte("a"), 3000)
+
+	// Nothing is throttled without a limiter
+	if reader := throttle(bytes.NewReader(content), nil); reader == nil {
+		t.Fatalf("throttle returned nil without a limiter")
+	}
+
+	// 3000 bytes at 1000 bytes per second with a full bucket of 1000 take about 2 seconds
+	limiter := rate.NewLimiter(rate.Limit(1000), 1000)
+	start := time.Now()
+	read, err := io.ReadAll(throttle(bytes.NewReader(content), limiter))
+	if err != nil {
+		t.Fatalf("Reading the throttled reader returned an error: %v", err)
+	}
+	if !bytes.Equal(read, content) {
+		t.Errorf("The throttled reader returned %d bytes, expected %d", len(read), len(content))
+	}
+	if elapsed := time.Since(start); elapsed < 1500*time.Millisecond {
+		t.Errorf("Reading took %v, expected the limiter to slow it down to about 2s", elapsed)
+	}
+}
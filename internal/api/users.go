@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+// GetTargetUser returns the user with the given login on the target instance, or nil if it does not exist.
+func GetTargetUser(login string) (*github.User, error) {
+	client := newGHRestClient(targetTokenSource(), "")
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	user, resp, err := client.Users.Get(ctx, login)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetSourceUser returns the user with the given login on the source instance, or nil if it does not exist.
+func GetSourceUser(login string) (*github.User, error) {
+	client := newGHRestClient(sourceTokenSource(), viper.GetString("SOURCE_HOSTNAME"))
+
+	ctx := context.WithValue(context.Background(), github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+	user, resp, err := client.Users.Get(ctx, login)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -2,6 +2,7 @@ package files
 
 import (
 	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"net/url"
 	"os"
@@ -45,6 +46,24 @@ func CreateJSON(data interface{}, filename string) error {
 	return nil
 }
 
+func CreateCSV(records [][]string, filename string) error {
+	// Create a new file
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Create a new CSV writer and write all records to the file
+	writer := csv.NewWriter(file)
+	err = writer.WriteAll(records)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // read repository list from file assuming each line is a repository
 func ReadRepositoryListFromFile(fileName string) ([]string, error) {
 	file, err := os.Open(fileName)
@@ -34,6 +34,34 @@ func TestCreateJSON(t *testing.T) {
 		t.Errorf("Failed to remove the test file: %v", err)
 	}
 }
+func TestCreateCSV(t *testing.T) {
+	records := [][]string{
+		{"source", "target"},
+		{"naruto", "naruto.uzumaki"},
+	}
+
+	filename := "test.csv"
+
+	err := files.CreateCSV(records, filename)
+	if err != nil {
+		t.Errorf("CreateCSV returned an error: %v", err)
+	}
+
+	// Verify the file contents
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Errorf("CreateCSV did not create the CSV file")
+	}
+	if string(content) != "source,target\nnaruto,naruto.uzumaki\n" {
+		t.Errorf("CreateCSV wrote unexpected content: %q", content)
+	}
+
+	// Clean up the test file
+	err = os.Remove(filename)
+	if err != nil {
+		t.Errorf("Failed to remove the test file: %v", err)
+	}
+}
 func TestOpenFile(t *testing.T) {
 	fileName := "test.txt"
 
@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// Setup installs the default slog logger. Entries at or above level are printed to the console,
+// through pterm for the text format or as JSON lines for the json format, and written to
+// filePath in the same format when it is set.
+func Setup(level string, format string, filePath string) error {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q, expected debug, info, warn or error", level)
+	}
+
+	var handlers []slog.Handler
+	options := &slog.HandlerOptions{Level: logLevel}
+	switch strings.ToLower(format) {
+	case "", "text":
+		if logLevel <= slog.LevelDebug {
+			pterm.EnableDebugMessages()
+		}
+		handlers = append(handlers, &consoleHandler{level: logLevel})
+	case "json":
+		handlers = append(handlers, slog.NewJSONHandler(os.Stdout, options))
+	default:
+		return fmt.Errorf("invalid log format %q, expected text or json", format)
+	}
+
+	if filePath != "" {
+		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open log file: %v", err)
+		}
+		handlers = append(handlers, newFileHandler(file, format, options))
+	}
+
+	slog.SetDefault(slog.New(multiHandler(handlers)))
+
+	return nil
+}
+
+func newFileHandler(writer io.Writer, format string, options *slog.HandlerOptions) slog.Handler {
+	if strings.ToLower(format) == "json" {
+		return slog.NewJSONHandler(writer, options)
+	}
+	return slog.NewTextHandler(writer, options)
+}
+
+// consoleHandler prints entries with the pterm prefix printers, so they match the rest of the output
+// and don't break running spinners, followed by their attributes as key=value pairs.
+type consoleHandler struct {
+	level slog.Leveler
+	attrs string
+	group string
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var builder strings.Builder
+	builder.WriteString(record.Message)
+	builder.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&builder, h.group, attr)
+		return true
+	})
+	text := builder.String()
+
+	switch {
+	case record.Level >= slog.LevelError:
+		pterm.Error.Println(text)
+	case record.Level >= slog.LevelWarn:
+		pterm.Warning.Println(text)
+	case record.Level >= slog.LevelInfo:
+		pterm.Info.Println(text)
+	default:
+		pterm.Debug.Println(text)
+	}
+
+	return nil
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var builder strings.Builder
+	builder.WriteString(h.attrs)
+	for _, attr := range attrs {
+		writeAttr(&builder, h.group, attr)
+	}
+	return &consoleHandler{level: h.level, attrs: builder.String(), group: h.group}
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &consoleHandler{level: h.level, attrs: h.attrs, group: h.group + name + "."}
+}
+
+// writeAttr writes an attribute as " key=value", quoting values that contain spaces.
+func writeAttr(builder *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, groupAttr := range attr.Value.Group() {
+			writeAttr(builder, group+attr.Key+".", groupAttr)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	builder.WriteString(" " + group + attr.Key + "=" + value)
+}
+
+// multiHandler sends entries to every handler that accepts them.
+type multiHandler []slog.Handler
+
+func (h multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h {
+		if handler.Enabled(ctx, record.Level) {
+			if err := handler.Handle(ctx, record.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (h multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (h multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(h))
+	for i, handler := range h {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
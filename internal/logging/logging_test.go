@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupLogFile(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	filePath := filepath.Join(t.TempDir(), "sync.log")
+	err := Setup("warn", "json", filePath)
+	if err != nil {
+		t.Fatalf("Setup returned an error: %v", err)
+	}
+
+	logger := slog.With("repository", "org/repo")
+	logger.Info("Not written below the warn level")
+	logger.Warn("Release skipped", "release", "v1.0.0", "request_id", "ABCD:1234")
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Unable to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Log file has %d entries, expected 1: %q", len(lines), content)
+	}
+
+	var entry map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Log entry is not JSON: %v", err)
+	}
+	expected := map[string]string{"level": "WARN", "msg": "Release skipped", "repository": "org/repo", "release": "v1.0.0", "request_id": "ABCD:1234"}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Log entry %s = %q, expected %q", key, entry[key], value)
+		}
+	}
+}
+
+func TestSetupValidation(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	if err := Setup("verbose", "text", ""); err == nil {
+		t.Errorf("Setup accepted an invalid log level")
+	}
+	if err := Setup("info", "xml", ""); err == nil {
+		t.Errorf("Setup accepted an invalid log format")
+	}
+}
+
+func TestWriteAttr(t *testing.T) {
+	var builder strings.Builder
+	writeAttr(&builder, "", slog.String("repository", "org/repo"))
+	writeAttr(&builder, "", slog.String("error", "not found"))
+	writeAttr(&builder, "asset.", slog.Int("size", 12))
+
+	expected := ` repository=org/repo error="not found" asset.size=12`
+	if builder.String() != expected {
+		t.Errorf("writeAttr wrote %q, expected %q", builder.String(), expected)
+	}
+}
@@ -0,0 +1,109 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	_ "time/tzdata" // time zones must resolve on hosts without a zoneinfo database
+
+	"github.com/google/go-github/v62/github"
+)
+
+// DefaultFooterTemplate records the original release timestamps, as the target release dates are the date of the sync.
+// Only mapped authors are @mentioned, the login of an unmapped author may belong to someone else on the target.
+const DefaultFooterTemplate = `{{with .CreatedAt}}
+> Release Originally Created on: {{date .}}{{end}}{{with .PublishedAt}}
+> Release Originally Published on: {{date .}}{{end}}{{with .Author}}
+> Release Originally Authored by: {{if $.Mapped}}@{{end}}{{.}}{{end}}`
+
+// DefaultDateLayout is the layout used by the footer template's date function.
+const DefaultDateLayout = "January 2, 2006 at 15:04:05 MST"
+
+// FooterData is the data available to footer templates. Dates are nil when the source release has none.
+// Author is the source author mapped through the handle mapping, SourceAuthor the original login,
+// and Mapped is set when the author has a mapping.
+type FooterData struct {
+	Name         string
+	Tag          string
+	Author       string
+	SourceAuthor string
+	Mapped       bool
+	SourceURL    string
+	CreatedAt    *time.Time
+	PublishedAt  *time.Time
+}
+
+// Footer appends a templated footer with source release details to release bodies.
+type Footer struct {
+	template *template.Template
+	location *time.Location
+	handles  HandleMap
+}
+
+// NewFooter parses a footer template. Dates are rendered in the given IANA time zone, UTC when empty,
+// and the release author is mapped through handles.
+func NewFooter(text string, timeZone string, handles HandleMap) (*Footer, error) {
+	location := time.UTC
+	if timeZone != "" {
+		var err error
+		location, err = time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+	}
+
+	footer := &Footer{location: location, handles: handles}
+	tmpl, err := template.New("footer").Funcs(template.FuncMap{
+		// date formats a time in the footer time zone, with an optional layout
+		"date": func(t time.Time, layout ...string) string {
+			if len(layout) > 0 {
+				return t.In(footer.location).Format(layout[0])
+			}
+			return t.In(footer.location).Format(DefaultDateLayout)
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid footer template: %v", err)
+	}
+	footer.template = tmpl
+
+	return footer, nil
+}
+
+// Apply renders the footer for a source release and appends it to the release body.
+func (f *Footer) Apply(release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	if release == nil {
+		return nil, fmt.Errorf("release is nil")
+	}
+
+	author, mapped := f.handles.Target(release.GetAuthor().GetLogin())
+	data := FooterData{
+		Name:         release.GetName(),
+		Tag:          release.GetTagName(),
+		Author:       author,
+		SourceAuthor: release.GetAuthor().GetLogin(),
+		Mapped:       mapped,
+		SourceURL:    release.GetHTMLURL(),
+	}
+	if release.CreatedAt != nil {
+		data.CreatedAt = &release.CreatedAt.Time
+	}
+	if release.PublishedAt != nil {
+		data.PublishedAt = &release.PublishedAt.Time
+	}
+
+	var footer strings.Builder
+	err := f.template.Execute(&footer, data)
+	if err != nil {
+		return release, fmt.Errorf("error rendering footer: %v", err)
+	}
+
+	releaseBody := release.GetBody()
+	if strings.TrimSpace(footer.String()) != "" {
+		releaseBody = releaseBody + "\n" + footer.String()
+	}
+	release.Body = &releaseBody
+
+	return release, nil
+}
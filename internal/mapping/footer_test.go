@@ -0,0 +1,142 @@
+package mapping
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestFooterWithNilBody(t *testing.T) {
+	footer, err := NewFooter(DefaultFooterTemplate, "", nil)
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+	if updatedRelease.Body == nil {
+		t.Errorf("Updated release body is nil")
+	}
+}
+
+func TestFooterWithNilCreatedAt(t *testing.T) {
+	footer, err := NewFooter(DefaultFooterTemplate, "", nil)
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{
+		Body: github.String("Test release body"),
+	}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+	if *updatedRelease.Body != "Test release body" {
+		t.Errorf("Updated release body contains made up timestamps: %q", *updatedRelease.Body)
+	}
+}
+
+func TestFooterWithNilPublishedAt(t *testing.T) {
+	footer, err := NewFooter(DefaultFooterTemplate, "", nil)
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{
+		Body:      github.String("Test release body"),
+		CreatedAt: &github.Timestamp{Time: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+	}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+
+	expected := "Test release body\n\n> Release Originally Created on: January 2, 2024 at 15:04:05 UTC"
+	if *updatedRelease.Body != expected {
+		t.Errorf("Updated release body = %q, expected %q", *updatedRelease.Body, expected)
+	}
+}
+
+func TestFooterTemplateAndTimeZone(t *testing.T) {
+	text := `---
+{{.Tag}} by @{{.Author}} (@{{.SourceAuthor}} on the source), originally published {{with .PublishedAt}}{{date . "2006-01-02 15:04 MST"}}{{end}} at {{.SourceURL}}`
+	footer, err := NewFooter(text, "America/Chicago", HandleMap{"naruto": "naruto.uzumaki"})
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{
+		Body:        github.String("Test release body"),
+		TagName:     github.String("v1.0.0"),
+		Author:      &github.User{Login: github.String("naruto")},
+		HTMLURL:     github.String("https://github.example.com/source-org/repo/releases/tag/v1.0.0"),
+		PublishedAt: &github.Timestamp{Time: time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+
+	expected := "v1.0.0 by @naruto.uzumaki (@naruto on the source), originally published 2024-07-01 07:00 CDT at https://github.example.com/source-org/repo/releases/tag/v1.0.0"
+	if !strings.HasSuffix(*updatedRelease.Body, expected) {
+		t.Errorf("Updated release body = %q, expected it to end with %q", *updatedRelease.Body, expected)
+	}
+}
+
+func TestNewFooterErrors(t *testing.T) {
+	if _, err := NewFooter(DefaultFooterTemplate, "Not/AZone", nil); err == nil {
+		t.Errorf("NewFooter did not return an error for an invalid time zone")
+	}
+	if _, err := NewFooter("{{.CreatedAt", "", nil); err == nil {
+		t.Errorf("NewFooter did not return an error for an invalid template")
+	}
+}
+
+func TestFooterWithAuthor(t *testing.T) {
+	footer, err := NewFooter(DefaultFooterTemplate, "", HandleMap{"naruto": "naruto.uzumaki"})
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{
+		Body:   github.String("Test release body"),
+		Author: &github.User{Login: github.String("Naruto")},
+	}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+
+	expected := "Test release body\n\n> Release Originally Authored by: @naruto.uzumaki"
+	if *updatedRelease.Body != expected {
+		t.Errorf("Updated release body = %q, expected %q", *updatedRelease.Body, expected)
+	}
+}
+
+func TestFooterWithUnmappedAuthor(t *testing.T) {
+	footer, err := NewFooter(DefaultFooterTemplate, "", HandleMap{"naruto": "naruto.uzumaki"})
+	if err != nil {
+		t.Fatalf("NewFooter returned an error: %v", err)
+	}
+
+	release := &github.RepositoryRelease{
+		Body:   github.String("Test release body"),
+		Author: &github.User{Login: github.String("sasuke")},
+	}
+	updatedRelease, err := footer.Apply(release)
+	if err != nil {
+		t.Errorf("Apply returned an error: %v", err)
+	}
+
+	// The source login may belong to an unrelated user on the target, so it isn't @mentioned
+	expected := "Test release body\n\n> Release Originally Authored by: sasuke"
+	if *updatedRelease.Body != expected {
+		t.Errorf("Updated release body = %q, expected %q", *updatedRelease.Body, expected)
+	}
+}
@@ -0,0 +1,195 @@
+package mapping
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HandleMap maps lower-cased source member handles to target member handles.
+type HandleMap map[string]string
+
+// handleEntry is a single mapping as read from a mapping file, with its line number for error reporting.
+type handleEntry struct {
+	line   int
+	source string
+	target string
+}
+
+// LoadHandleMap reads and validates a handle mapping file. The format is chosen by extension:
+// .json, .yaml and .yml files hold either a "source: target" object or a list of
+// {source, target} objects; anything else is read as CSV. CSV files may be a plain
+// "source,target" file or a GEI mannequin mapping CSV ("mannequin-user,mannequin-id,target-user").
+func LoadHandleMap(filePath string) (HandleMap, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []handleEntry
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json", ".yaml", ".yml":
+		entries, err = readStructuredHandles(file)
+	default:
+		entries, err = readCSVHandles(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading mapping file %s: %v", filePath, err)
+	}
+
+	handleMap, err := validateHandles(entries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mapping file %s:\n%v", filePath, err)
+	}
+
+	return handleMap, nil
+}
+
+// Lookup returns the target handle for a source handle, or the source handle itself when it isn't mapped.
+func (h HandleMap) Lookup(handle string) string {
+	target, _ := h.Target(handle)
+	return target
+}
+
+// Target returns the target handle for a source handle and whether it is mapped, the source handle
+// itself being returned when it isn't.
+func (h HandleMap) Target(handle string) (string, bool) {
+	if target, ok := h[strings.ToLower(handle)]; ok {
+		return target, true
+	}
+	return handle, false
+}
+
+func readCSVHandles(reader io.Reader) ([]handleEntry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	sourceColumn, targetColumn := 0, 1
+	mannequin := false
+
+	var entries []handleEntry
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		if line == 1 {
+			// Detect header rows, including the GEI mannequin mapping format
+			header := strings.ToLower(strings.Join(record, ","))
+			if header == "source,target" || strings.HasPrefix(header, "source,target,") {
+				continue
+			}
+			if header == "mannequin-user,mannequin-id,target-user" {
+				sourceColumn, targetColumn = 0, 2
+				mannequin = true
+				continue
+			}
+		}
+
+		if len(record) <= targetColumn {
+			return nil, fmt.Errorf("line %d: expected at least %d columns, got %d", line, targetColumn+1, len(record))
+		}
+
+		entry := handleEntry{
+			line:   line,
+			source: strings.TrimSpace(record[sourceColumn]),
+			target: strings.TrimSpace(record[targetColumn]),
+		}
+
+		// Mannequins that have not been assigned a target user yet are left unmapped
+		if mannequin && entry.target == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func readStructuredHandles(reader io.Reader) ([]handleEntry, error) {
+	// YAML is a superset of JSON, so both formats are read through a YAML node tree
+	var document yaml.Node
+	err := yaml.NewDecoder(reader).Decode(&document)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root := &document
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	var entries []handleEntry
+	switch root.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			entries = append(entries, handleEntry{
+				line:   root.Content[i].Line,
+				source: strings.TrimSpace(root.Content[i].Value),
+				target: strings.TrimSpace(root.Content[i+1].Value),
+			})
+		}
+	case yaml.SequenceNode:
+		for _, item := range root.Content {
+			var pair struct {
+				Source string `yaml:"source"`
+				Target string `yaml:"target"`
+			}
+			if err := item.Decode(&pair); err != nil {
+				return nil, fmt.Errorf("line %d: %v", item.Line, err)
+			}
+			entries = append(entries, handleEntry{
+				line:   item.Line,
+				source: strings.TrimSpace(pair.Source),
+				target: strings.TrimSpace(pair.Target),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("line %d: expected an object or a list of source/target pairs", root.Line)
+	}
+
+	return entries, nil
+}
+
+func validateHandles(entries []handleEntry) (HandleMap, error) {
+	var errs []error
+	handleMap := make(HandleMap)
+	seen := make(map[string]int)
+
+	for _, entry := range entries {
+		switch {
+		case entry.source == "" || entry.target == "":
+			errs = append(errs, fmt.Errorf("line %d: source and target handles must not be blank", entry.line))
+		case strings.EqualFold(entry.source, entry.target):
+			errs = append(errs, fmt.Errorf("line %d: %q is mapped to itself", entry.line, entry.source))
+		case seen[strings.ToLower(entry.source)] != 0:
+			errs = append(errs, fmt.Errorf("line %d: duplicate mapping for %q (first mapped on line %d)", entry.line, entry.source, seen[strings.ToLower(entry.source)]))
+		default:
+			seen[strings.ToLower(entry.source)] = entry.line
+			handleMap[strings.ToLower(entry.source)] = entry.target
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return handleMap, nil
+}
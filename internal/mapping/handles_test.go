@@ -0,0 +1,119 @@
+package mapping
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeMappingFile(t *testing.T, filePath string, content string) {
+	t.Helper()
+
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(filePath) })
+}
+
+func TestLoadHandleMapFormats(t *testing.T) {
+	expected := HandleMap{
+		"naruto": "naruto.uzumaki",
+		"sasuke": "sasuke.uchiha",
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+	}{
+		{
+			name:     "CSV with header",
+			filePath: "test-header.csv",
+			content:  "source,target\nnaruto,naruto.uzumaki\nsasuke,sasuke.uchiha\n",
+		},
+		{
+			name:     "generated CSV with confidence column",
+			filePath: "test-generated.csv",
+			content:  "source,target,confidence\nnaruto,naruto.uzumaki,high\nsasuke,sasuke.uchiha,low\n",
+		},
+		{
+			name:     "GEI mannequin CSV",
+			filePath: "test-mannequins.csv",
+			content:  "mannequin-user,mannequin-id,target-user\nnaruto,M_1,naruto.uzumaki\nsasuke,M_2,sasuke.uchiha\nkakashi,M_3,\n",
+		},
+		{
+			name:     "JSON object",
+			filePath: "test.json",
+			content:  `{"naruto": "naruto.uzumaki", "sasuke": "sasuke.uchiha"}`,
+		},
+		{
+			name:     "YAML list",
+			filePath: "test.yaml",
+			content:  "- source: naruto\n  target: naruto.uzumaki\n- source: sasuke\n  target: sasuke.uchiha\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeMappingFile(t, test.filePath, test.content)
+
+			handleMap, err := LoadHandleMap(test.filePath)
+			if err != nil {
+				t.Fatalf("LoadHandleMap returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(handleMap, expected) {
+				t.Errorf("Loaded handle map = %v, expected %v", handleMap, expected)
+			}
+		})
+	}
+}
+
+func TestLoadHandleMapValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		errors   []string
+	}{
+		{
+			name:     "single column row",
+			filePath: "test-columns.csv",
+			content:  "naruto,naruto.uzumaki\nsasuke\n",
+			errors:   []string{"line 2: expected at least 2 columns"},
+		},
+		{
+			name:     "duplicate, blank and self mappings",
+			filePath: "test-invalid.csv",
+			content:  "source,target\nnaruto,naruto.uzumaki\nNaruto,someone\nsasuke,\nkakashi,kakashi\n",
+			errors: []string{
+				`line 3: duplicate mapping for "Naruto" (first mapped on line 2)`,
+				"line 4: source and target handles must not be blank",
+				`line 5: "kakashi" is mapped to itself`,
+			},
+		},
+		{
+			name:     "duplicate YAML keys",
+			filePath: "test-invalid.yml",
+			content:  "naruto: naruto.uzumaki\nsasuke: sasuke.uchiha\nnaruto: someone\n",
+			errors:   []string{`line 3: duplicate mapping for "naruto" (first mapped on line 1)`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeMappingFile(t, test.filePath, test.content)
+
+			_, err := LoadHandleMap(test.filePath)
+			if err == nil {
+				t.Fatalf("LoadHandleMap did not return an error")
+			}
+			for _, expected := range test.errors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("LoadHandleMap error %q does not contain %q", err, expected)
+				}
+			}
+		})
+	}
+}
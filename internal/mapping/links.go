@@ -0,0 +1,280 @@
+package mapping
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// NumberMap maps source issue/PR numbers to target numbers. Keys are lower-cased
+// "owner/repo" or "repo" names; the empty key holds the unqualified mappings, which only apply
+// to the repository being synced.
+type NumberMap map[string]map[int]int
+
+// LinkRewriter rewrites issue, pull request, commit, compare and release references
+// in a release body from the source repository to the target repository.
+type LinkRewriter struct {
+	SourceHost  string
+	TargetHost  string
+	SourceOwner string
+	TargetOwner string
+	Repository  string
+	Numbers     NumberMap
+	// GitLab rewrites paths to their GitLab form, e.g. /pull/12 to /-/merge_requests/12
+	GitLab bool
+	// PullRequests holds the pull request numbers of the source repository, whose short references
+	// are rewritten to GitLab merge request references such as !12
+	PullRequests map[int]bool
+
+	pattern *regexp.Regexp
+}
+
+// NewLinkRewriter creates a rewriter for a source repository using the configured
+// source hostname and organizations.
+func NewLinkRewriter(owner string, repository string, numbers NumberMap) *LinkRewriter {
+	sourceHost := strings.TrimSuffix(viper.GetString("SOURCE_HOSTNAME"), "/")
+	if sourceHost == "" {
+		sourceHost = "github.com"
+	}
+
+	rewriter := &LinkRewriter{
+		SourceHost:  sourceHost,
+		TargetHost:  "github.com",
+		SourceOwner: owner,
+		TargetOwner: viper.GetString("TARGET_ORGANIZATION"),
+		Repository:  repository,
+		Numbers:     numbers,
+	}
+	if strings.EqualFold(viper.GetString("TARGET_TYPE"), "gitlab") {
+		rewriter.GitLab = true
+		rewriter.TargetHost = "gitlab.com"
+		if targetHost := viper.GetString("TARGET_HOSTNAME"); targetHost != "" {
+			rewriter.TargetHost = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(targetHost, "https://"), "http://"), "/")
+		}
+	}
+
+	return rewriter
+}
+
+// LoadNumberMap reads an issue/PR number mapping CSV. Rows are either
+// "source,target" or "repository,source,target"; non-numeric rows such as headers are skipped.
+func LoadNumberMap(filePath string) (NumberMap, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make(NumberMap)
+	for index, record := range records {
+		var repository string
+		switch len(record) {
+		case 2:
+		case 3:
+			repository = strings.ToLower(strings.TrimSpace(record[0]))
+			record = record[1:]
+		default:
+			return nil, fmt.Errorf("line %d: expected 2 or 3 columns, got %d", index+1, len(record))
+		}
+
+		source, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(record[0]), "#"))
+		if err != nil {
+			continue // header row
+		}
+		target, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(record[1]), "#"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid target number %q", index+1, record[1])
+		}
+
+		if numbers[repository] == nil {
+			numbers[repository] = make(map[int]int)
+		}
+		numbers[repository][source] = target
+	}
+
+	return numbers, nil
+}
+
+// Lookup returns the target number for a reference in the given source repository, from the mappings
+// qualified with that repository.
+func (n NumberMap) Lookup(owner string, repository string, number int) (int, bool) {
+	keys := []string{
+		strings.ToLower(owner + "/" + repository),
+		strings.ToLower(repository),
+	}
+	for _, key := range keys {
+		if target, ok := n[key][number]; ok {
+			return target, true
+		}
+	}
+	return number, false
+}
+
+// Unqualified reports whether the map has mappings without a repository.
+func (n NumberMap) Unqualified() bool {
+	return len(n[""]) > 0
+}
+
+// lookupNumber returns the target number for a reference, applying unqualified mappings only to
+// references to the repository being synced.
+func (r *LinkRewriter) lookupNumber(owner string, repository string, number int) (int, bool) {
+	if target, ok := r.Numbers.Lookup(owner, repository, number); ok {
+		return target, true
+	}
+	if strings.EqualFold(repository, r.Repository) {
+		if target, ok := r.Numbers[""][number]; ok {
+			return target, true
+		}
+	}
+	return number, false
+}
+
+// Rewrite returns the body with every reference pointing to the target repository.
+// A nil rewriter returns the body unchanged.
+func (r *LinkRewriter) Rewrite(body string) string {
+	if r == nil {
+		return body
+	}
+	if r.pattern == nil {
+		// Either a URL on the source host, or a short reference such as #12 or owner/repo#12
+		r.pattern = regexp.MustCompile(`(?i)https?://` + regexp.QuoteMeta(r.SourceHost) + `(/[^\s)\]>"'<]*)?` +
+			`|(^|[^\w/#&.-])(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range r.pattern.FindAllStringSubmatchIndex(body, -1) {
+		builder.WriteString(body[last:match[0]])
+		last = match[1]
+
+		group := func(n int) string {
+			if match[2*n] < 0 {
+				return ""
+			}
+			return body[match[2*n]:match[2*n+1]]
+		}
+
+		if match[10] < 0 {
+			// URL branch: group 1 is the (optional) path
+			if match[2] < 0 && hostContinues(body, match[1]) {
+				builder.WriteString(body[match[0]:match[1]])
+				continue
+			}
+			builder.WriteString("https://" + r.TargetHost + r.rewritePath(group(1)))
+			continue
+		}
+
+		builder.WriteString(group(2))
+		builder.WriteString(r.rewriteShortReference(group(3), group(4), group(5)))
+	}
+	builder.WriteString(body[last:])
+
+	return builder.String()
+}
+
+// hostContinues reports whether the source host matched up to end is only the start of another host,
+// such as github.com.example.io, github.company.com or the same host on another port.
+func hostContinues(body string, end int) bool {
+	isAlphanumeric := func(i int) bool {
+		return i < len(body) && (body[i] >= 'a' && body[i] <= 'z' || body[i] >= 'A' && body[i] <= 'Z' || body[i] >= '0' && body[i] <= '9')
+	}
+	if end >= len(body) {
+		return false
+	}
+	switch body[end] {
+	case '-', '_':
+		return true
+	case '.', ':':
+		return isAlphanumeric(end + 1)
+	}
+	return isAlphanumeric(end)
+}
+
+func (r *LinkRewriter) rewritePath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) < 2 || !strings.EqualFold(segments[0], r.SourceOwner) {
+		return path
+	}
+
+	owner, repository := segments[0], segments[1]
+	segments[0] = r.TargetOwner
+
+	// Renumber /issues/N and /pull/N, keeping any trailing path or fragment
+	if len(segments) >= 4 && (segments[2] == "issues" || segments[2] == "pull") {
+		digits := segments[3]
+		suffix := ""
+		if i := strings.IndexFunc(digits, func(c rune) bool { return c < '0' || c > '9' }); i >= 0 {
+			digits, suffix = digits[:i], digits[i:]
+		}
+		if number, err := strconv.Atoi(digits); err == nil {
+			if target, ok := r.lookupNumber(owner, repository, number); ok {
+				segments[3] = strconv.Itoa(target) + suffix
+			}
+		}
+	}
+
+	if r.GitLab && len(segments) >= 3 {
+		if section, ok := gitlabSections[segments[2]]; ok {
+			segments[2] = section
+			segments = append(segments[:2], append([]string{"-"}, segments[2:]...)...)
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// gitlabSections maps the repository sections of GitHub URLs to their GitLab name, which follows "/-/".
+var gitlabSections = map[string]string{
+	"issues":   "issues",
+	"pull":     "merge_requests",
+	"commit":   "commit",
+	"compare":  "compare",
+	"releases": "releases",
+	"tree":     "tree",
+	"blob":     "blob",
+}
+
+func (r *LinkRewriter) rewriteShortReference(owner string, repository string, number string) string {
+	reference := "#" + number
+	if owner != "" {
+		reference = owner + "/" + repository + reference
+	}
+
+	if owner == "" {
+		owner, repository = r.SourceOwner, r.Repository
+	} else if !strings.EqualFold(owner, r.SourceOwner) {
+		return reference // reference to a repository outside the migration
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return reference
+	}
+	source := n
+	if target, ok := r.lookupNumber(owner, repository, n); ok {
+		n = target
+	}
+
+	// GitLab reads #N as an issue, merge requests are referenced as !N
+	separator := "#"
+	if r.GitLab && strings.EqualFold(repository, r.Repository) && r.PullRequests[source] {
+		separator = "!"
+	}
+
+	if reference[0] == '#' {
+		return separator + strconv.Itoa(n)
+	}
+	return r.TargetOwner + "/" + repository + separator + strconv.Itoa(n)
+}
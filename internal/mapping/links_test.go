@@ -0,0 +1,181 @@
+package mapping
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func newTestLinkRewriter(numbers NumberMap) *LinkRewriter {
+	return &LinkRewriter{
+		SourceHost:  "ghes.example.com",
+		TargetHost:  "github.com",
+		SourceOwner: "source-org",
+		TargetOwner: "target-org",
+		Repository:  "repo",
+		Numbers:     numbers,
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	rewriter := newTestLinkRewriter(NumberMap{
+		"":                    {1: 101},
+		"source-org/other":    {7: 70},
+		"source-org/repo":     {12: 112},
+		"unrelated-org/repo2": {3: 30},
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "pull request URL",
+			body:     "* Fix bug by @naruto in https://ghes.example.com/source-org/repo/pull/12",
+			expected: "* Fix bug by @naruto in https://github.com/target-org/repo/pull/112",
+		},
+		{
+			name:     "issue URL with trailing punctuation",
+			body:     "See https://ghes.example.com/source-org/repo/issues/12.",
+			expected: "See https://github.com/target-org/repo/issues/112.",
+		},
+		{
+			name:     "compare URL",
+			body:     "**Full Changelog**: https://ghes.example.com/source-org/repo/compare/v1.0.0...v1.1.0",
+			expected: "**Full Changelog**: https://github.com/target-org/repo/compare/v1.0.0...v1.1.0",
+		},
+		{
+			name:     "commit URL",
+			body:     "[abc123](https://ghes.example.com/source-org/repo/commit/abc123)",
+			expected: "[abc123](https://github.com/target-org/repo/commit/abc123)",
+		},
+		{
+			name:     "URL outside source organization keeps its path",
+			body:     "https://ghes.example.com/someone-else/repo/pull/12",
+			expected: "https://github.com/someone-else/repo/pull/12",
+		},
+		{
+			name:     "short reference",
+			body:     "Fixes #12 and #1 but not #99",
+			expected: "Fixes #112 and #101 but not #99",
+		},
+		{
+			name:     "cross repository reference",
+			body:     "Related to source-org/other#7 and unrelated-org/repo2#3",
+			expected: "Related to target-org/other#70 and unrelated-org/repo2#3",
+		},
+		{
+			name:     "unqualified mappings only apply to the synced repository",
+			body:     "Fixes source-org/repo#1, not source-org/other#1 or https://ghes.example.com/source-org/other/issues/1",
+			expected: "Fixes target-org/repo#101, not target-org/other#1 or https://github.com/target-org/other/issues/1",
+		},
+		{
+			name:     "headings and entities are untouched",
+			body:     "# Changes\n&#12; and page#12",
+			expected: "# Changes\n&#12; and page#12",
+		},
+		{
+			name:     "URL on a host starting with the source host is untouched",
+			body:     "https://ghes.example.com.evil.io/source-org/repo/pull/12 https://ghes.example.company.com/x https://ghes.example.com:8443/x",
+			expected: "https://ghes.example.com.evil.io/source-org/repo/pull/12 https://ghes.example.company.com/x https://ghes.example.com:8443/x",
+		},
+		{
+			name:     "bare source host URL at the end of a sentence",
+			body:     "Moved from https://ghes.example.com.",
+			expected: "Moved from https://github.com.",
+		},
+		{
+			name:     "URL on another host is untouched",
+			body:     "https://example.org/source-org/repo/pull/12",
+			expected: "https://example.org/source-org/repo/pull/12",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := rewriter.Rewrite(test.body); actual != test.expected {
+				t.Errorf("Rewrite(%q) = %q, expected %q", test.body, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRewriteLinksWithoutNumberMap(t *testing.T) {
+	rewriter := newTestLinkRewriter(nil)
+
+	body := "Fixes #12 in https://ghes.example.com/source-org/repo/pull/12"
+	expected := "Fixes #12 in https://github.com/target-org/repo/pull/12"
+
+	if actual := rewriter.Rewrite(body); actual != expected {
+		t.Errorf("Rewrite(%q) = %q, expected %q", body, actual, expected)
+	}
+}
+
+func TestLoadNumberMap(t *testing.T) {
+	filePath := "numbers.csv"
+
+	err := os.WriteFile(filePath, []byte("source,target\n1,101\n#2,#102\nsource-org/repo,3,103\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer os.Remove(filePath)
+
+	numbers, err := LoadNumberMap(filePath)
+	if err != nil {
+		t.Fatalf("LoadNumberMap returned an error: %v", err)
+	}
+
+	expected := NumberMap{
+		"":                {1: 101, 2: 102},
+		"source-org/repo": {3: 103},
+	}
+	if !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("Loaded number map = %v, expected %v", numbers, expected)
+	}
+}
+
+func TestRewriteLinksWithoutRewriter(t *testing.T) {
+	var rewriter *LinkRewriter
+
+	body := "Fixes #12 in https://ghes.example.com/source-org/repo/pull/12"
+	if actual := rewriter.Rewrite(body); actual != body {
+		t.Errorf("Rewrite(%q) = %q, expected the body unchanged", body, actual)
+	}
+}
+
+func TestRewriteLinksToGitLab(t *testing.T) {
+	rewriter := newTestLinkRewriter(NumberMap{"": {12: 112}})
+	rewriter.TargetHost = "gitlab.example.com"
+	rewriter.GitLab = true
+
+	tests := map[string]string{
+		"https://ghes.example.com/source-org/repo/pull/12":                 "https://gitlab.example.com/target-org/repo/-/merge_requests/112",
+		"https://ghes.example.com/source-org/repo/issues/12#note":          "https://gitlab.example.com/target-org/repo/-/issues/112#note",
+		"https://ghes.example.com/source-org/repo/compare/v1.0.0...v1.1.0": "https://gitlab.example.com/target-org/repo/-/compare/v1.0.0...v1.1.0",
+		"https://ghes.example.com/source-org/repo/releases/tag/v1.0.0":     "https://gitlab.example.com/target-org/repo/-/releases/tag/v1.0.0",
+		"https://ghes.example.com/source-org/repo":                         "https://gitlab.example.com/target-org/repo",
+		"https://ghes.example.com/source-org/repo/wiki":                    "https://gitlab.example.com/target-org/repo/wiki",
+	}
+	for body, expected := range tests {
+		if actual := rewriter.Rewrite(body); actual != expected {
+			t.Errorf("Rewrite(%q) = %q, expected %q", body, actual, expected)
+		}
+	}
+}
+
+func TestRewriteShortReferencesToGitLab(t *testing.T) {
+	rewriter := newTestLinkRewriter(NumberMap{"": {12: 112}})
+	rewriter.PullRequests = map[int]bool{12: true}
+
+	body := "Merged #12, fixes #13 and source-org/repo#12, see source-org/other#12"
+	if actual := rewriter.Rewrite(body); actual != "Merged #112, fixes #13 and target-org/repo#112, see target-org/other#12" {
+		t.Errorf("Rewrite to GitHub returned %q", actual)
+	}
+
+	rewriter.GitLab = true
+	expected := "Merged !112, fixes #13 and target-org/repo!112, see target-org/other#12"
+	if actual := rewriter.Rewrite(body); actual != expected {
+		t.Errorf("Rewrite(%q) = %q, expected %q", body, actual, expected)
+	}
+}
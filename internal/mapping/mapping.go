@@ -1,39 +1,15 @@
 package mapping
 
 import (
-	"encoding/csv"
-	"fmt"
-	"os"
+	"regexp"
 	"strings"
-	"time"
-
-	"github.com/google/go-github/v62/github"
-	"github.com/spf13/viper"
 )
 
-func loadHandleMap(filePath string) (map[string]string, error) {
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-
-	handleMap := make(map[string]string)
-	for _, record := range records {
-		handleMap[record[0]] = record[1]
-	}
+// mentionPattern matches an @handle not preceded by a word character, along with a trailing slash for team mentions.
+// Handles may contain an underscore, as in the shortcode suffix of Enterprise Managed Users (@jdoe_acme).
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@/.-])@([A-Za-z0-9](?:[A-Za-z0-9]|[-_][A-Za-z0-9]){0,38})/?`)
 
-	return handleMap, nil
-}
-
-func ModifyReleaseBody(releaseBody *string, filePath string) (*string, error) {
+func ModifyReleaseBody(releaseBody *string, handles HandleMap, links *LinkRewriter) *string {
 	// Modify release body to map new handles and map old urls to new urls
 
 	updatedReleaseBody := ""
@@ -41,57 +17,53 @@ func ModifyReleaseBody(releaseBody *string, filePath string) (*string, error) {
 		updatedReleaseBody = *releaseBody
 	}
 
-	if viper.GetString("SOURCE_HOSTNAME") != "" {
-		// Replace source hostname with GHEC hostname github.com
-		updatedReleaseBody = strings.ReplaceAll(updatedReleaseBody, viper.GetString("SOURCE_HOSTNAME"), "github.com")
-	}
-
-	// Replace source organization with target organization
-	updatedReleaseBody = strings.ReplaceAll(updatedReleaseBody, viper.GetString("SOURCE_ORGANIZATION"), viper.GetString("TARGET_ORGANIZATION"))
-
-	// Load handle map from file
-	handleMap, err := loadHandleMap(filePath)
-	if err != nil {
-		return releaseBody, err //return the original release body if an error occurs
-	}
+	// Rewrite issue, PR, commit, compare and release references to the target repository
+	updatedReleaseBody = links.Rewrite(updatedReleaseBody)
 
 	// Replace old handles with new handles
-	for source, target := range handleMap {
-		updatedReleaseBody = strings.ReplaceAll(updatedReleaseBody, source, target)
-	}
+	updatedReleaseBody = replaceMentions(updatedReleaseBody, handles)
 
-	return &updatedReleaseBody, nil
+	return &updatedReleaseBody
 }
 
-func AddSourceTimeStamps(release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
-	if release == nil {
-		return nil, fmt.Errorf("release is nil")
+// replaceMentions maps the handles of whole @mentions, leaving longer handles, team mentions, email
+// addresses and URLs that merely contain a source handle untouched.
+func replaceMentions(body string, handles HandleMap) string {
+	if len(handles) == 0 {
+		return body
 	}
 
-	releaseBody := ""
-	if release.Body != nil {
-		releaseBody = *release.Body
+	var builder strings.Builder
+	last := 0
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(body, -1) {
+		if strings.HasSuffix(body[match[0]:match[1]], "/") {
+			continue // team mention
+		}
+		builder.WriteString(body[last:match[2]])
+		builder.WriteString(handles.Lookup(body[match[2]:match[3]]))
+		last = match[3]
 	}
+	builder.WriteString(body[last:])
 
-	var createdAt, publishedAt string
-	now := time.Now().Format("January 2, 2006 at 15:04:05 CST")
-
-	if release.CreatedAt != nil {
-		createdAt = release.CreatedAt.Format("January 2, 2006 at 15:04:05 CST")
-	} else {
-		createdAt = now
-	}
+	return builder.String()
+}
 
-	if release.PublishedAt != nil {
-		publishedAt = release.PublishedAt.Format("January 2, 2006 at 15:04:05 CST")
-	} else {
-		publishedAt = now
+// ExtractMentions returns the unique user handles @mentioned in a release body, in order of appearance.
+// Team mentions (@org/team) and email addresses are ignored.
+func ExtractMentions(body string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		if strings.HasSuffix(match[0], "/") {
+			continue // team mention
+		}
+		handle := match[1]
+		if !seen[strings.ToLower(handle)] {
+			seen[strings.ToLower(handle)] = true
+			mentions = append(mentions, handle)
+		}
 	}
 
-	// Add source timestamps to release body
-	releaseBody = releaseBody + "\n\n" + ">Release Originally Created on: " + createdAt + "\n" + "> Release Originally Published on: " + publishedAt
-
-	release.Body = &releaseBody
-
-	return release, nil
+	return mentions
 }
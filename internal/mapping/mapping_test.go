@@ -6,9 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
-	"time"
 
-	"github.com/google/go-github/v62/github"
 	"github.com/spf13/viper"
 )
 
@@ -37,13 +35,13 @@ func TestLoadHandleMap(t *testing.T) {
 	writer.Flush()
 
 	// Load handle map from the test file
-	handleMap, err := loadHandleMap(filePath)
+	handleMap, err := LoadHandleMap(filePath)
 	if err != nil {
-		t.Errorf("loadHandleMap returned an error: %v", err)
+		t.Errorf("LoadHandleMap returned an error: %v", err)
 	}
 
 	// Verify the loaded handle map
-	expectedHandleMap := map[string]string{
+	expectedHandleMap := HandleMap{
 		"key1": "value1",
 		"key2": "value2",
 	}
@@ -87,12 +85,12 @@ func TestModifyReleaseBody(t *testing.T) {
 	viper.Set("SOURCE_ORGANIZATION", "source-org")
 	viper.Set("TARGET_ORGANIZATION", "target-org")
 
-	// Modify the release body
-	updatedReleaseBody, err := ModifyReleaseBody(&releaseBody, filePath)
-
+	// Load the handle map and modify the release body
+	handleMap, err := LoadHandleMap(filePath)
 	if err != nil {
-		t.Errorf("ModifyReleaseBody returned an error: %v", err)
+		t.Fatalf("LoadHandleMap returned an error: %v", err)
 	}
+	updatedReleaseBody := ModifyReleaseBody(&releaseBody, handleMap, NewLinkRewriter("source-org", "repo", nil))
 	expectedReleaseBody := releaseBody
 	expectedReleaseBody = strings.ReplaceAll(expectedReleaseBody, "example.com", "github.com")
 	expectedReleaseBody = strings.ReplaceAll(expectedReleaseBody, "source-org", "target-org")
@@ -140,12 +138,12 @@ func TestModifyReleaseBodyWithNilBody(t *testing.T) {
 	viper.Set("SOURCE_ORGANIZATION", "source-org")
 	viper.Set("TARGET_ORGANIZATION", "target-org")
 
-	// Modify the release body
-	updatedReleaseBody, err := ModifyReleaseBody(releaseBody, filePath)
-
+	// Load the handle map and modify the release body
+	handleMap, err := LoadHandleMap(filePath)
 	if err != nil {
-		t.Errorf("ModifyReleaseBody returned an error: %v", err)
+		t.Fatalf("LoadHandleMap returned an error: %v", err)
 	}
+	updatedReleaseBody := ModifyReleaseBody(releaseBody, handleMap, NewLinkRewriter("source-org", "repo", nil))
 
 	if updatedReleaseBody != nil && *updatedReleaseBody != "" {
 		t.Errorf("Modified release body is not nil")
@@ -158,40 +156,27 @@ func TestModifyReleaseBodyWithNilBody(t *testing.T) {
 	}
 }
 
-func TestAddSourceTimeStampsWithNilBody(t *testing.T) {
-	release := &github.RepositoryRelease{}
-	updatedRelease, err := AddSourceTimeStamps(release)
-	if err != nil {
-		t.Errorf("AddSourceTimeStamps returned an error: %v", err)
-	}
-	if updatedRelease.Body == nil {
-		t.Errorf("Updated release body is nil")
-	}
-}
+func TestModifyReleaseBodyReplacesWholeMentions(t *testing.T) {
+	viper.Set("SOURCE_HOSTNAME", "example.com")
+	viper.Set("TARGET_ORGANIZATION", "target-org")
+	handles := HandleMap{"bob": "robert", "robert": "bobby", "jdoe_acme": "jane-doe"}
+	releaseBody := "Thanks @bob, @Bob and @bobby! See https://example.com/source-org/bobcat/issues, @bob/team and bob@example.com (@robert, @jdoe_acme, @jdoe)"
 
-func TestAddSourceTimeStampsWithNilCreatedAt(t *testing.T) {
-	release := &github.RepositoryRelease{
-		Body: github.String("Test release body"),
-	}
-	updatedRelease, err := AddSourceTimeStamps(release)
-	if err != nil {
-		t.Errorf("AddSourceTimeStamps returned an error: %v", err)
-	}
-	if !strings.Contains(*updatedRelease.Body, "Release Originally Created on:") {
-		t.Errorf("Updated release body does not contain the expected created at timestamp")
+	updatedReleaseBody := ModifyReleaseBody(&releaseBody, handles, NewLinkRewriter("source-org", "repo", nil))
+
+	expected := "Thanks @robert, @robert and @bobby! See https://github.com/target-org/bobcat/issues, @bob/team and bob@example.com (@bobby, @jane-doe, @jdoe)"
+	if *updatedReleaseBody != expected {
+		t.Errorf("ModifyReleaseBody returned %q, expected %q", *updatedReleaseBody, expected)
 	}
 }
 
-func TestAddSourceTimeStampsWithNilPublishedAt(t *testing.T) {
-	release := &github.RepositoryRelease{
-		Body:      github.String("Test release body"),
-		CreatedAt: &github.Timestamp{Time: time.Now()},
-	}
-	updatedRelease, err := AddSourceTimeStamps(release)
-	if err != nil {
-		t.Errorf("AddSourceTimeStamps returned an error: %v", err)
-	}
-	if !strings.Contains(*updatedRelease.Body, "Release Originally Published on:") {
-		t.Errorf("Updated release body does not contain the expected published at timestamp")
+func TestExtractMentions(t *testing.T) {
+	body := "Thanks @naruto, @Sasuke and @naruto! Reviewed by @org/team, mail naruto@example.com\n@kakashi-sensei and @jdoe_acme"
+
+	mentions := ExtractMentions(body)
+
+	expected := []string{"naruto", "Sasuke", "kakashi-sensei", "jdoe_acme"}
+	if !reflect.DeepEqual(mentions, expected) {
+		t.Errorf("ExtractMentions returned %v, expected %v", mentions, expected)
 	}
 }
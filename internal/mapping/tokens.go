@@ -0,0 +1,71 @@
+package mapping
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// TokenMap maps lower-cased target handles to personal tokens used to create releases as that user.
+type TokenMap map[string]string
+
+// LoadTokenMap reads a "login,token" CSV of target users that supplied a token to author their releases.
+func LoadTokenMap(filePath string) (TokenMap, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	csvReader := csv.NewReader(file)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	var errs []error
+	tokens := make(TokenMap)
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading token file %s: %v", filePath, err)
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		if line == 1 && strings.EqualFold(strings.Join(record, ","), "login,token") {
+			continue // header row
+		}
+
+		if len(record) < 2 {
+			errs = append(errs, fmt.Errorf("line %d: expected 2 columns, got %d", line, len(record)))
+			continue
+		}
+
+		login := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(record[0], "@")))
+		token := strings.TrimSpace(record[1])
+		switch {
+		case login == "" || token == "":
+			errs = append(errs, fmt.Errorf("line %d: login and token must not be blank", line))
+		case tokens[login] != "":
+			errs = append(errs, fmt.Errorf("line %d: duplicate token for %q", line, login))
+		default:
+			tokens[login] = token
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid token file %s:\n%v", filePath, errors.Join(errs...))
+	}
+
+	return tokens, nil
+}
+
+// Lookup returns the token supplied by a target user, if any.
+func (t TokenMap) Lookup(login string) (string, bool) {
+	token, ok := t[strings.ToLower(login)]
+	return token, ok
+}
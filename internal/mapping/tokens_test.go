@@ -0,0 +1,51 @@
+package mapping
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadTokenMap(t *testing.T) {
+	filePath := "tokens.csv"
+	writeMappingFile(t, filePath, "login,token\n@Naruto.Uzumaki,ghp_naruto\nsasuke.uchiha, ghp_sasuke\n")
+
+	tokens, err := LoadTokenMap(filePath)
+	if err != nil {
+		t.Fatalf("LoadTokenMap returned an error: %v", err)
+	}
+
+	if token, ok := tokens.Lookup("naruto.uzumaki"); !ok || token != "ghp_naruto" {
+		t.Errorf("Lookup returned %q, %v, expected ghp_naruto, true", token, ok)
+	}
+	if token, ok := tokens.Lookup("sasuke.uchiha"); !ok || token != "ghp_sasuke" {
+		t.Errorf("Lookup returned %q, %v, expected ghp_sasuke, true", token, ok)
+	}
+	if _, ok := tokens.Lookup("kakashi"); ok {
+		t.Errorf("Lookup returned a token for a user that did not supply one")
+	}
+}
+
+func TestLoadTokenMapShortRow(t *testing.T) {
+	filePath := "tokens-short.csv"
+	writeMappingFile(t, filePath, "alice\n")
+
+	_, err := LoadTokenMap(filePath)
+	if err == nil || !strings.Contains(err.Error(), "line 1: expected 2 columns, got 1") {
+		t.Errorf("LoadTokenMap returned %v, expected a column count error for line 1", err)
+	}
+}
+
+func TestLoadTokenMapValidation(t *testing.T) {
+	filePath := "tokens-invalid.csv"
+	writeMappingFile(t, filePath, "naruto,ghp_naruto\nNaruto,ghp_other\nsasuke,\n")
+
+	_, err := LoadTokenMap(filePath)
+	if err == nil {
+		t.Fatalf("LoadTokenMap did not return an error")
+	}
+	for _, expected := range []string{`line 2: duplicate token for "naruto"`, "line 3: login and token must not be blank"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("LoadTokenMap error %q does not contain %q", err, expected)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event types sent during a sync.
+const (
+	RunStarted          = "run.started"
+	RepositoryCompleted = "repository.completed"
+	ReleaseFailed       = "release.failed"
+	RunFinished         = "run.finished"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the request body, as "sha256=<hex>", when a secret is set.
+	SignatureHeader = "X-Hub-Signature-256"
+	// EventHeader carries the event type.
+	EventHeader = "X-Migrate-Releases-Event"
+)
+
+// Event is a notification about the progress of a sync.
+type Event struct {
+	Type       string    `json:"event"`
+	RunID      string    `json:"run_id"`
+	RunURL     string    `json:"run_url,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	Message    string    `json:"message"`
+	Repository string    `json:"repository,omitempty"`
+	Release    string    `json:"release,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// Data holds the details of the event, such as the result of a repository or the totals of a run
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Notifier posts events to a webhook. The methods of a nil Notifier do nothing.
+type Notifier struct {
+	url    string
+	secret string
+	format string
+	runID  string
+	runURL string
+	client *http.Client
+}
+
+// New creates a notifier posting the events of a run to url, shaped as the given format:
+// json for the events themselves, or slack or teams for incoming webhooks of those tools.
+// Bodies are signed with secret when it is set.
+func New(url string, secret string, format string, runID string, runURL string) (*Notifier, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case "":
+		format = "json"
+	case "json", "slack", "teams":
+	default:
+		return nil, fmt.Errorf("invalid notification format %q, expected json, slack or teams", format)
+	}
+
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("invalid webhook URL %q, expected an http or https URL", url)
+	}
+
+	return &Notifier{
+		url:    url,
+		secret: secret,
+		format: format,
+		runID:  runID,
+		runURL: runURL,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Send posts an event. Notifications never fail a sync, so errors are only logged.
+func (n *Notifier) Send(event Event) {
+	if n == nil {
+		return
+	}
+	event.RunID = n.runID
+	event.RunURL = n.runURL
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	err := n.post(event)
+	if err != nil {
+		slog.Warn("Unable to send webhook notification", "event", event.Type, "error", err)
+	}
+}
+
+func (n *Notifier) post(event Event) error {
+	body, err := json.Marshal(n.payload(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gh-migrate-releases")
+	req.Header.Set(EventHeader, event.Type)
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the signature of a body, as sent in the SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// payload shapes an event for the configured format.
+func (n *Notifier) payload(event Event) interface{} {
+	text := event.Message
+	if event.RunURL != "" && (event.Type == RunStarted || event.Type == RunFinished) {
+		text += " (" + event.RunURL + ")"
+	}
+
+	switch n.format {
+	case "slack":
+		return map[string]interface{}{"text": text}
+	case "teams":
+		// An adaptive card message, accepted by Teams workflow webhooks and incoming webhooks
+		return map[string]interface{}{
+			"type": "message",
+			"attachments": []interface{}{
+				map[string]interface{}{
+					"contentType": "application/vnd.microsoft.card.adaptive",
+					"content": map[string]interface{}{
+						"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+						"type":    "AdaptiveCard",
+						"version": "1.2",
+						"body": []interface{}{
+							map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true},
+						},
+					},
+				},
+			},
+		}
+	default:
+		return event
+	}
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+func newTestServer(t *testing.T, status int) (*httptest.Server, chan request) {
+	t.Helper()
+
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestSendJSON(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK)
+
+	notifier, err := New(server.URL, "secret", "", "run-1", "https://github.com/mona/migrations/actions/runs/42")
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	notifier.Send(Event{Type: ReleaseFailed, Message: "failed", Repository: "mona/repo", Release: "v1.0.0", Error: "boom"})
+
+	received := <-requests
+	if received.header.Get(EventHeader) != ReleaseFailed {
+		t.Errorf("%s header = %q, expected %q", EventHeader, received.header.Get(EventHeader), ReleaseFailed)
+	}
+	if signature := received.header.Get(SignatureHeader); signature != Sign("secret", received.body) {
+		t.Errorf("%s header = %q, expected %q", SignatureHeader, signature, Sign("secret", received.body))
+	}
+
+	var event Event
+	if err := json.Unmarshal(received.body, &event); err != nil {
+		t.Fatalf("Body is not an event: %v", err)
+	}
+	if event.RunID != "run-1" || event.Release != "v1.0.0" || event.Error != "boom" || event.Timestamp.IsZero() {
+		t.Errorf("Received event %+v", event)
+	}
+}
+
+func TestSendShapes(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   "slack",
+			expected: `{"text":"Release sync finished"}`,
+		},
+		{
+			format: "teams",
+			expected: `{"attachments":[{"content":{"$schema":"http://adaptivecards.io/schemas/adaptive-card.json",` +
+				`"body":[{"text":"Release sync finished","type":"TextBlock","wrap":true}],"type":"AdaptiveCard","version":"1.2"},` +
+				`"contentType":"application/vnd.microsoft.card.adaptive"}],"type":"message"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			server, requests := newTestServer(t, http.StatusOK)
+
+			notifier, err := New(server.URL, "", test.format, "run-1", "")
+			if err != nil {
+				t.Fatalf("New returned an error: %v", err)
+			}
+			notifier.Send(Event{Type: RunFinished, Message: "Release sync finished"})
+
+			received := <-requests
+			if string(received.body) != test.expected {
+				t.Errorf("Body = %s, expected %s", received.body, test.expected)
+			}
+			if received.header.Get(SignatureHeader) != "" {
+				t.Errorf("Body was signed without a secret")
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New("https://example.com/hook", "", "xml", "run-1", ""); err == nil {
+		t.Errorf("New with an invalid format did not return an error")
+	}
+	if _, err := New("example.com/hook", "", "json", "run-1", ""); err == nil {
+		t.Errorf("New with an invalid URL did not return an error")
+	}
+}
+
+func TestNilNotifier(t *testing.T) {
+	var notifier *Notifier
+	notifier.Send(Event{Type: RunStarted})
+}
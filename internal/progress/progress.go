@@ -0,0 +1,222 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+var (
+	mu          sync.Mutex
+	interactive = term.IsTerminal(int(os.Stdout.Fd()))
+	repository  string
+	release     string
+)
+
+// Setup switches output to plain, timestamped log lines when stdout is not a terminal,
+// so spinners and progress bars don't garble CI logs.
+func Setup() {
+	if interactive {
+		return
+	}
+	pterm.DisableStyling()
+	pterm.SetDefaultOutput(NewTimestampWriter(os.Stdout))
+}
+
+// Interactive reports whether live spinners and progress bars are shown.
+func Interactive() bool {
+	return interactive
+}
+
+// SetRepository records the repository being processed, for the overall progress prefix.
+func SetRepository(current int, total int, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	repository = fmt.Sprintf("repository %d/%d %s", current, total, name)
+	release = ""
+}
+
+// SetRelease records the release being processed, for the overall progress prefix.
+func SetRelease(current int, total int, tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+	release = fmt.Sprintf("release %d/%d %s", current, total, tag)
+}
+
+// Prefix returns the overall progress, such as "[repository 2/10 repo · release 3/25 v1.2.0]",
+// or "" outside of a sync.
+func Prefix() string {
+	mu.Lock()
+	defer mu.Unlock()
+	switch {
+	case repository == "":
+		return ""
+	case release == "":
+		return "[" + repository + "] "
+	default:
+		return "[" + repository + " · " + release + "] "
+	}
+}
+
+// Transfer reports the progress of a download or upload, as a progress bar with throughput and ETA
+// on a terminal, and as periodic log lines otherwise.
+type Transfer struct {
+	action  string
+	name    string
+	total   int64
+	started time.Time
+
+	mu         sync.Mutex
+	done       int64
+	bar        *pterm.ProgressbarPrinter
+	lastUpdate time.Time
+	lastLogged time.Time
+}
+
+// logInterval is how often plain output reports the progress of a transfer.
+const logInterval = 10 * time.Second
+
+// StartTransfer starts reporting a transfer of total bytes, or of an unknown size when total is 0 or less.
+// The action is a verb such as "Downloading".
+func StartTransfer(action string, name string, total int64) *Transfer {
+	t := &Transfer{action: action, name: name, total: total, started: time.Now()}
+	t.lastLogged = t.started
+
+	if interactive && total > 0 {
+		t.bar, _ = pterm.DefaultProgressbar.
+			WithTotal(int(total)).
+			WithTitle(t.title()).
+			WithShowCount(false).
+			WithShowElapsedTime(false).
+			WithRemoveWhenDone(true).
+			Start()
+	} else {
+		pterm.Info.Printf("%s%s %s (%s)\n", Prefix(), action, name, totalSize(total))
+	}
+
+	return t
+}
+
+// Reader returns a reader that reports the bytes read from reader as transferred.
+func (t *Transfer) Reader(reader io.Reader) io.Reader {
+	return &countingReader{reader: reader, transfer: t}
+}
+
+// Add reports n more bytes as transferred.
+func (t *Transfer) Add(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done += int64(n)
+	now := time.Now()
+
+	if t.bar != nil {
+		// Redraw the title at most a few times per second to keep throughput readable
+		if now.Sub(t.lastUpdate) >= 250*time.Millisecond {
+			t.lastUpdate = now
+			t.bar.UpdateTitle(t.title())
+		}
+		if t.bar.IsActive {
+			t.bar.Add(n)
+		}
+		return
+	}
+
+	if now.Sub(t.lastLogged) >= logInterval {
+		t.lastLogged = now
+		pterm.Info.Printf("%s%s\n", Prefix(), t.title())
+	}
+}
+
+// Finish stops reporting the transfer and logs its outcome when it succeeded.
+func (t *Transfer) Finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.bar != nil && t.bar.IsActive {
+		t.bar.Stop()
+	}
+	if err != nil {
+		return
+	}
+
+	elapsed := time.Since(t.started)
+	pterm.Info.Printf("%s%s %s: %s in %s (%s/s)\n", Prefix(), pastTense(t.action), t.name, size(t.done),
+		elapsed.Round(time.Second/10), size(rate(t.done, elapsed)))
+}
+
+// title describes the transfer with its throughput and, when the size is known, its ETA.
+func (t *Transfer) title() string {
+	elapsed := time.Since(t.started)
+	throughput := rate(t.done, elapsed)
+
+	title := fmt.Sprintf("%s%s %s %s/%s %s/s", Prefix(), t.action, t.name, size(t.done), totalSize(t.total), size(throughput))
+	if t.total > 0 && throughput > 0 {
+		eta := time.Duration(float64(t.total-t.done) / float64(throughput) * float64(time.Second))
+		title += " ETA " + eta.Round(time.Second).String()
+	}
+
+	return title
+}
+
+type countingReader struct {
+	reader   io.Reader
+	transfer *Transfer
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.transfer.Add(n)
+	}
+	return n, err
+}
+
+// rate returns the bytes per second of n bytes transferred in elapsed.
+func rate(n int64, elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(n) / elapsed.Seconds())
+}
+
+// size formats a number of bytes for humans, such as "12.3 MB". Unknown sizes are shown as "?".
+func size(n int64) string {
+	if n < 0 {
+		return "?"
+	}
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+// totalSize formats the size of a transfer, which is unknown when 0 or less.
+func totalSize(n int64) string {
+	if n <= 0 {
+		return "?"
+	}
+	return size(n)
+}
+
+func pastTense(action string) string {
+	switch action {
+	case "Downloading":
+		return "Downloaded"
+	case "Uploading":
+		return "Uploaded"
+	default:
+		return action
+	}
+}
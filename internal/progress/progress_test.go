@@ -0,0 +1,72 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewTimestampWriter(&output)
+	writer.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	io.WriteString(writer, "Fetching releases\n\r          \rSUCCESS  Done")
+	io.WriteString(writer, "\nINFO  Total: 3\n")
+
+	expected := "2024-01-02T03:04:05Z Fetching releases\n" +
+		"2024-01-02T03:04:05Z SUCCESS  Done\n" +
+		"2024-01-02T03:04:05Z INFO  Total: 3\n"
+	if output.String() != expected {
+		t.Errorf("TimestampWriter wrote %q, expected %q", output.String(), expected)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	t.Cleanup(func() { repository, release = "", "" })
+
+	if prefix := Prefix(); prefix != "" {
+		t.Errorf("Prefix() = %q outside of a sync, expected none", prefix)
+	}
+
+	SetRepository(2, 10, "repo")
+	if prefix := Prefix(); prefix != "[repository 2/10 repo] " {
+		t.Errorf("Prefix() = %q", prefix)
+	}
+
+	SetRelease(3, 25, "v1.2.0")
+	if prefix := Prefix(); prefix != "[repository 2/10 repo · release 3/25 v1.2.0] " {
+		t.Errorf("Prefix() = %q", prefix)
+	}
+}
+
+func TestTransferReader(t *testing.T) {
+	content := strings.Repeat("a", 5000)
+
+	transfer := StartTransfer("Downloading", "app.zip", int64(len(content)))
+	read, err := io.ReadAll(transfer.Reader(strings.NewReader(content)))
+	transfer.Finish(err)
+
+	if string(read) != content {
+		t.Errorf("The transfer reader returned %d bytes, expected %d", len(read), len(content))
+	}
+	if transfer.done != int64(len(content)) {
+		t.Errorf("The transfer counted %d bytes, expected %d", transfer.done, len(content))
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := map[int64]string{
+		-1:         "?",
+		999:        "999 B",
+		1500:       "1.5 kB",
+		12_300_000: "12.3 MB",
+	}
+	for n, expected := range tests {
+		if actual := size(n); actual != expected {
+			t.Errorf("size(%d) = %q, expected %q", n, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimestampWriter prefixes every line written to it with a timestamp. Lines redrawn with carriage
+// returns, as spinners do, are split into separate lines and blank redraws are dropped.
+type TimestampWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+	buffer bytes.Buffer
+	now    func() time.Time
+}
+
+// NewTimestampWriter returns a writer adding timestamps to the lines written to writer.
+func NewTimestampWriter(writer io.Writer) *TimestampWriter {
+	return &TimestampWriter{writer: writer, now: time.Now}
+}
+
+func (w *TimestampWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buffer.Write(p)
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest of it is written
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			break
+		}
+
+		for _, part := range strings.Split(strings.TrimRight(line, "\r\n"), "\r") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			if _, err := io.WriteString(w.writer, w.now().UTC().Format(time.RFC3339)+" "+part+"\n"); err != nil {
+				return len(p), err
+			}
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,122 @@
+package releases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultChangelogTemplate lists every release with its date, author and notes. Only mapped authors are
+// @mentioned, the login of an unmapped author may belong to someone else on the target.
+const DefaultChangelogTemplate = `# Changelog
+{{range .Releases}}
+## [{{.Name}}]({{.URL}}){{with .PublishedAt}} - {{date . "2006-01-02"}}{{end}}{{if .Prerelease}} (prerelease){{end}}
+{{if .Author}}
+Released by {{if .Mapped}}@{{end}}{{.Author}}
+{{end}}{{with .Body}}
+{{.}}
+{{end}}{{end}}`
+
+// ChangelogData is the data available to changelog templates.
+type ChangelogData struct {
+	// Repository is the "owner/name" of the repository the changelog is for
+	Repository string
+	Releases   []ChangelogEntry
+}
+
+// ChangelogEntry is a release as rendered in a changelog. Dates are nil when the release has none.
+// Author is the release author mapped through the handle mapping, SourceAuthor the original login,
+// and Mapped is set when the author has a mapping.
+type ChangelogEntry struct {
+	Name         string
+	Tag          string
+	Author       string
+	SourceAuthor string
+	Mapped       bool
+	URL          string
+	Body         string
+	Prerelease   bool
+	CreatedAt    *time.Time
+	PublishedAt  *time.Time
+}
+
+// Changelog renders release entries with a template.
+type Changelog struct {
+	template *template.Template
+	location *time.Location
+}
+
+// NewChangelog parses a changelog template. Dates are rendered in the given IANA time zone, UTC when empty.
+func NewChangelog(text string, timeZone string) (*Changelog, error) {
+	location := time.UTC
+	if timeZone != "" {
+		var err error
+		location, err = time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+	}
+
+	changelog := &Changelog{location: location}
+	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
+		// date formats a time in the changelog time zone, with an optional layout
+		"date": func(t time.Time, layout ...string) string {
+			if len(layout) > 0 {
+				return t.In(changelog.location).Format(layout[0])
+			}
+			return t.In(changelog.location).Format("January 2, 2006")
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid changelog template: %v", err)
+	}
+	changelog.template = tmpl
+
+	return changelog, nil
+}
+
+// Render renders the changelog, ending with a single newline.
+func (c *Changelog) Render(data ChangelogData) (string, error) {
+	var builder strings.Builder
+	err := c.template.Execute(&builder, data)
+	if err != nil {
+		return "", fmt.Errorf("error rendering changelog: %v", err)
+	}
+
+	return strings.TrimRight(builder.String(), "\n") + "\n", nil
+}
+
+// SortEntries orders entries by publication date, or creation date for releases that were never published,
+// either "newest" or "oldest" first. Entries without dates keep their relative order at the end.
+func SortEntries(entries []ChangelogEntry, order string) error {
+	var newestFirst bool
+	switch strings.ToLower(order) {
+	case "", "newest":
+		newestFirst = true
+	case "oldest":
+	default:
+		return fmt.Errorf("invalid order %q, expected newest or oldest", order)
+	}
+
+	date := func(entry ChangelogEntry) *time.Time {
+		if entry.PublishedAt != nil {
+			return entry.PublishedAt
+		}
+		return entry.CreatedAt
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := date(entries[i]), date(entries[j])
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if newestFirst {
+			return a.After(*b)
+		}
+		return a.Before(*b)
+	})
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package releases
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func changelogDate(value string) *time.Time {
+	date, _ := time.Parse(time.RFC3339, value)
+	return &date
+}
+
+func TestRenderChangelog(t *testing.T) {
+	changelog, err := NewChangelog(DefaultChangelogTemplate, "America/Chicago")
+	if err != nil {
+		t.Fatalf("NewChangelog returned an error: %v", err)
+	}
+
+	actual, err := changelog.Render(ChangelogData{
+		Repository: "target-org/repo",
+		Releases: []ChangelogEntry{
+			{
+				Name:        "v1.1.0-rc.1",
+				URL:         "https://github.com/target-org/repo/releases/tag/v1.1.0-rc.1",
+				Author:      "sasuke",
+				Prerelease:  true,
+				PublishedAt: changelogDate("2024-02-01T03:00:00Z"),
+			},
+			{
+				Name:        "Version 1",
+				URL:         "https://github.com/target-org/repo/releases/tag/v1.0.0",
+				Author:      "naruto.uzumaki",
+				Mapped:      true,
+				Body:        "* First release",
+				PublishedAt: changelogDate("2024-01-15T12:00:00Z"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Render returned an error: %v", err)
+	}
+
+	expected := "# Changelog\n\n" +
+		"## [v1.1.0-rc.1](https://github.com/target-org/repo/releases/tag/v1.1.0-rc.1) - 2024-01-31 (prerelease)\n\n" +
+		"Released by sasuke\n\n" +
+		"## [Version 1](https://github.com/target-org/repo/releases/tag/v1.0.0) - 2024-01-15\n\n" +
+		"Released by @naruto.uzumaki\n\n" +
+		"* First release\n"
+	if actual != expected {
+		t.Errorf("Render() = %q, expected %q", actual, expected)
+	}
+}
+
+func TestNewChangelogErrors(t *testing.T) {
+	if _, err := NewChangelog("{{range}", ""); err == nil {
+		t.Errorf("NewChangelog with an invalid template did not return an error")
+	}
+	if _, err := NewChangelog(DefaultChangelogTemplate, "Mars/Olympus"); err == nil {
+		t.Errorf("NewChangelog with an invalid time zone did not return an error")
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	entries := []ChangelogEntry{
+		{Tag: "undated"},
+		{Tag: "v1", PublishedAt: changelogDate("2024-01-01T00:00:00Z")},
+		{Tag: "v3-draft", CreatedAt: changelogDate("2024-03-01T00:00:00Z")},
+		{Tag: "v2", PublishedAt: changelogDate("2024-02-01T00:00:00Z")},
+	}
+
+	tags := func() []string {
+		var tags []string
+		for _, entry := range entries {
+			tags = append(tags, entry.Tag)
+		}
+		return tags
+	}
+
+	if err := SortEntries(entries, "newest"); err != nil {
+		t.Fatalf("SortEntries returned an error: %v", err)
+	}
+	if expected := []string{"v3-draft", "v2", "v1", "undated"}; !reflect.DeepEqual(tags(), expected) {
+		t.Errorf("Newest first order = %v, expected %v", tags(), expected)
+	}
+
+	if err := SortEntries(entries, "oldest"); err != nil {
+		t.Fatalf("SortEntries returned an error: %v", err)
+	}
+	if expected := []string{"v1", "v2", "v3-draft", "undated"}; !reflect.DeepEqual(tags(), expected) {
+		t.Errorf("Oldest first order = %v, expected %v", tags(), expected)
+	}
+
+	if err := SortEntries(entries, "alphabetical"); err == nil {
+		t.Errorf("SortEntries with an invalid order did not return an error")
+	}
+}
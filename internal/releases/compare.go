@@ -0,0 +1,70 @@
+package releases
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Difference is a discrepancy between a source release and its target counterpart.
+type Difference struct {
+	Tag     string
+	Kind    string
+	Details string
+}
+
+// Compare pairs source and target releases by tag and returns every source release that is missing
+// from the target or differs in name, draft or prerelease flags, or assets.
+func Compare(source []*github.RepositoryRelease, target []*github.RepositoryRelease) []Difference {
+	targetByTag := make(map[string]*github.RepositoryRelease)
+	for _, release := range target {
+		targetByTag[release.GetTagName()] = release
+	}
+
+	var differences []Difference
+	for _, sourceRelease := range source {
+		tag := sourceRelease.GetTagName()
+		targetRelease, ok := targetByTag[tag]
+		if !ok {
+			differences = append(differences, Difference{Tag: tag, Kind: "missing release", Details: sourceRelease.GetName()})
+			continue
+		}
+
+		if sourceRelease.GetName() != targetRelease.GetName() {
+			differences = append(differences, Difference{Tag: tag, Kind: "name mismatch",
+				Details: fmt.Sprintf("source %q, target %q", sourceRelease.GetName(), targetRelease.GetName())})
+		}
+		if sourceRelease.GetDraft() != targetRelease.GetDraft() {
+			differences = append(differences, Difference{Tag: tag, Kind: "draft mismatch",
+				Details: fmt.Sprintf("source %t, target %t", sourceRelease.GetDraft(), targetRelease.GetDraft())})
+		}
+		if sourceRelease.GetPrerelease() != targetRelease.GetPrerelease() {
+			differences = append(differences, Difference{Tag: tag, Kind: "prerelease mismatch",
+				Details: fmt.Sprintf("source %t, target %t", sourceRelease.GetPrerelease(), targetRelease.GetPrerelease())})
+		}
+
+		differences = append(differences, compareAssets(tag, sourceRelease.Assets, targetRelease.Assets)...)
+	}
+
+	return differences
+}
+
+func compareAssets(tag string, source []*github.ReleaseAsset, target []*github.ReleaseAsset) []Difference {
+	targetByName := make(map[string]*github.ReleaseAsset)
+	for _, asset := range target {
+		targetByName[asset.GetName()] = asset
+	}
+
+	var differences []Difference
+	for _, sourceAsset := range source {
+		targetAsset, ok := targetByName[sourceAsset.GetName()]
+		if !ok {
+			differences = append(differences, Difference{Tag: tag, Kind: "missing asset", Details: sourceAsset.GetName()})
+		} else if sourceAsset.GetSize() != targetAsset.GetSize() {
+			differences = append(differences, Difference{Tag: tag, Kind: "asset size mismatch",
+				Details: fmt.Sprintf("%s: source %d bytes, target %d bytes", sourceAsset.GetName(), sourceAsset.GetSize(), targetAsset.GetSize())})
+		}
+	}
+
+	return differences
+}
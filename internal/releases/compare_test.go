@@ -0,0 +1,77 @@
+package releases
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestCompare(t *testing.T) {
+	source := []*github.RepositoryRelease{
+		{
+			TagName: github.String("v1.0.0"),
+			Name:    github.String("v1.0.0"),
+			Assets: []*github.ReleaseAsset{
+				{Name: github.String("app.zip"), Size: github.Int(100)},
+				{Name: github.String("app.tar.gz"), Size: github.Int(200)},
+			},
+		},
+		{
+			TagName:    github.String("v1.1.0"),
+			Name:       github.String("Release 1.1"),
+			Prerelease: github.Bool(true),
+			Assets: []*github.ReleaseAsset{
+				{Name: github.String("app.zip"), Size: github.Int(100)},
+			},
+		},
+		{
+			TagName: github.String("v2.0.0"),
+			Name:    github.String("v2.0.0"),
+		},
+	}
+	target := []*github.RepositoryRelease{
+		{
+			TagName: github.String("v1.0.0"),
+			Name:    github.String("v1.0.0"),
+			Assets: []*github.ReleaseAsset{
+				{Name: github.String("app.zip"), Size: github.Int(100)},
+				{Name: github.String("app.tar.gz"), Size: github.Int(200)},
+			},
+		},
+		{
+			TagName: github.String("v1.1.0"),
+			Name:    github.String("Release 1.1.0"),
+			Assets: []*github.ReleaseAsset{
+				{Name: github.String("app.zip"), Size: github.Int(99)},
+			},
+		},
+	}
+
+	differences := Compare(source, target)
+
+	expected := []Difference{
+		{Tag: "v1.1.0", Kind: "name mismatch", Details: `source "Release 1.1", target "Release 1.1.0"`},
+		{Tag: "v1.1.0", Kind: "prerelease mismatch", Details: "source true, target false"},
+		{Tag: "v1.1.0", Kind: "asset size mismatch", Details: "app.zip: source 100 bytes, target 99 bytes"},
+		{Tag: "v2.0.0", Kind: "missing release", Details: "v2.0.0"},
+	}
+	if !reflect.DeepEqual(differences, expected) {
+		t.Errorf("Compare returned %v, expected %v", differences, expected)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	releases := []*github.RepositoryRelease{
+		{
+			TagName: github.String("v1.0.0"),
+			Name:    github.String("v1.0.0"),
+			Draft:   github.Bool(true),
+			Assets:  []*github.ReleaseAsset{{Name: github.String("app.zip"), Size: github.Int(100)}},
+		},
+	}
+
+	if differences := Compare(releases, releases); len(differences) != 0 {
+		t.Errorf("Compare returned differences for identical releases: %v", differences)
+	}
+}
@@ -0,0 +1,92 @@
+package releases
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Status is the state of a source release in the target repository.
+type Status int
+
+const (
+	// Absent means no target release uses the tag of the source release yet.
+	Absent Status = iota
+	// Synced means the target release was created from this source release by a prior run.
+	Synced
+	// Conflict means the tag of the source release is used by a target release created some other way.
+	Conflict
+)
+
+func (s Status) String() string {
+	switch s {
+	case Synced:
+		return "synced by a prior run"
+	case Conflict:
+		return "foreign conflict"
+	default:
+		return "absent"
+	}
+}
+
+// Index looks up the target counterparts of the releases of one source repository.
+type Index struct {
+	byTag      map[string]*github.RepositoryRelease
+	bySourceID map[int64]*github.RepositoryRelease
+	sourceHost string
+	sourceRepo string
+}
+
+// NewIndex indexes the releases of a target repository by tag and by the source release their marker points to.
+// Only markers for the given source host and "owner/repo" source repository are considered.
+func NewIndex(target []*github.RepositoryRelease, sourceHost string, sourceRepository string) *Index {
+	index := &Index{
+		byTag:      make(map[string]*github.RepositoryRelease),
+		bySourceID: make(map[int64]*github.RepositoryRelease),
+		sourceHost: sourceHost,
+		sourceRepo: sourceRepository,
+	}
+
+	for _, release := range target {
+		index.Add(release)
+	}
+
+	return index
+}
+
+// Add indexes a target release, such as one just created.
+func (i *Index) Add(release *github.RepositoryRelease) {
+	i.byTag[release.GetTagName()] = release
+	if marker, ok := ParseMarker(release.GetBody()); ok && marker.Matches(i.sourceHost, i.sourceRepo, "") {
+		i.bySourceID[marker.SourceReleaseID] = release
+	}
+}
+
+// Classify returns the status of a source release in the target repository, with the matching
+// target release when there is one.
+func (i *Index) Classify(source *github.RepositoryRelease) (Status, *github.RepositoryRelease) {
+	if release, ok := i.bySourceID[source.GetID()]; ok {
+		return Synced, release
+	}
+	if release, ok := i.byTag[source.GetTagName()]; ok {
+		return Conflict, release
+	}
+	return Absent, nil
+}
+
+// MissingAssets returns the assets of a source release that its target counterpart doesn't have, by name.
+func MissingAssets(source *github.RepositoryRelease, target *github.RepositoryRelease) []*github.ReleaseAsset {
+	existing := make(map[string]bool)
+	for _, asset := range target.Assets {
+		existing[strings.ToLower(asset.GetName())] = true
+	}
+
+	var missing []*github.ReleaseAsset
+	for _, asset := range source.Assets {
+		if !existing[strings.ToLower(asset.GetName())] {
+			missing = append(missing, asset)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,63 @@
+package releases
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func markedRelease(tag string, marker Marker) *github.RepositoryRelease {
+	return &github.RepositoryRelease{TagName: github.String(tag), Body: github.String(AddMarker("Notes", marker))}
+}
+
+func TestIndexClassify(t *testing.T) {
+	ours := Marker{SourceHost: "github.example.com", SourceRepository: "source-org/repo", SourceReleaseID: 1, RunID: "run-1"}
+	otherRepository := Marker{SourceHost: "github.example.com", SourceRepository: "source-org/other", SourceReleaseID: 2, RunID: "run-1"}
+
+	index := NewIndex([]*github.RepositoryRelease{
+		markedRelease("v1.0.0", ours),
+		markedRelease("v2.0.0", otherRepository),
+		{TagName: github.String("v3.0.0"), Body: github.String("Created by hand")},
+	}, "github.example.com", "source-org/repo")
+
+	tests := []struct {
+		name     string
+		source   *github.RepositoryRelease
+		expected Status
+	}{
+		{"created by a prior run", &github.RepositoryRelease{ID: github.Int64(1), TagName: github.String("v1.0.0")}, Synced},
+		{"tag used by a release of another source", &github.RepositoryRelease{ID: github.Int64(2), TagName: github.String("v2.0.0")}, Conflict},
+		{"tag used by an unmarked release", &github.RepositoryRelease{ID: github.Int64(3), TagName: github.String("v3.0.0")}, Conflict},
+		{"not in target", &github.RepositoryRelease{ID: github.Int64(4), TagName: github.String("v4.0.0")}, Absent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, _ := index.Classify(test.source)
+			if status != test.expected {
+				t.Errorf("Classify returned %v, expected %v", status, test.expected)
+			}
+		})
+	}
+
+	// Releases created during the run are recognized afterwards
+	index.Add(markedRelease("v4.0.0", Marker{SourceHost: "github.example.com", SourceRepository: "source-org/repo", SourceReleaseID: 4}))
+	if status, _ := index.Classify(&github.RepositoryRelease{ID: github.Int64(4), TagName: github.String("v4.0.0")}); status != Synced {
+		t.Errorf("Classify returned %v for an added release, expected %v", status, Synced)
+	}
+}
+
+func TestMissingAssets(t *testing.T) {
+	source := &github.RepositoryRelease{Assets: []*github.ReleaseAsset{
+		{Name: github.String("app.zip")},
+		{Name: github.String("app.tar.gz")},
+	}}
+	target := &github.RepositoryRelease{Assets: []*github.ReleaseAsset{
+		{Name: github.String("app.zip")},
+	}}
+
+	missing := MissingAssets(source, target)
+	if len(missing) != 1 || missing[0].GetName() != "app.tar.gz" {
+		t.Errorf("MissingAssets returned %v, expected only app.tar.gz", missing)
+	}
+}
@@ -0,0 +1,70 @@
+package releases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// markerPrefix starts the hidden HTML comment identifying releases created by a sync
+const markerPrefix = "<!-- gh-migrate-releases: "
+
+var markerPattern = regexp.MustCompile(`<!-- gh-migrate-releases: (\{.*?\}) -->`)
+
+// Marker identifies the source release a target release was created from, and the sync run that created it.
+// CreatedTag is set when the tag didn't exist in the target and was created along with the release.
+type Marker struct {
+	SourceHost       string `json:"source_host"`
+	SourceRepository string `json:"source_repository"`
+	SourceReleaseID  int64  `json:"source_release_id"`
+	RunID            string `json:"run_id"`
+	CreatedTag       bool   `json:"created_tag,omitempty"`
+}
+
+// NewRunID returns a unique, sortable identifier for a sync run.
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// String renders the marker as a hidden HTML comment.
+func (m Marker) String() string {
+	content, _ := json.Marshal(m)
+	return markerPrefix + string(content) + " -->"
+}
+
+// AddMarker appends the marker to a release body, replacing any marker already present.
+func AddMarker(body string, marker Marker) string {
+	body = strings.TrimRight(markerPattern.ReplaceAllString(body, ""), "\n")
+	if body == "" {
+		return marker.String()
+	}
+	return body + "\n\n" + marker.String()
+}
+
+// ParseMarker returns the marker of a release body, if it has one.
+func ParseMarker(body string) (Marker, bool) {
+	var marker Marker
+
+	match := markerPattern.FindStringSubmatch(body)
+	if match == nil {
+		return marker, false
+	}
+	if err := json.Unmarshal([]byte(match[1]), &marker); err != nil {
+		return marker, false
+	}
+
+	return marker, true
+}
+
+// Matches returns whether the marker is for the given source repository on the given host, and
+// from the given run when runID is set. Hosts and repositories are compared case-insensitively.
+func (m Marker) Matches(sourceHost string, sourceRepository string, runID string) bool {
+	return strings.EqualFold(m.SourceHost, sourceHost) &&
+		strings.EqualFold(m.SourceRepository, sourceRepository) &&
+		(runID == "" || m.RunID == runID)
+}
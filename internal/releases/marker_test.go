@@ -0,0 +1,77 @@
+package releases
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkerRoundTrip(t *testing.T) {
+	marker := Marker{
+		SourceHost:       "github.example.com",
+		SourceRepository: "source-org/repo",
+		SourceReleaseID:  42,
+		RunID:            NewRunID(),
+	}
+
+	body := AddMarker("Release notes\n", marker)
+	if !strings.HasPrefix(body, "Release notes\n\n<!-- gh-migrate-releases: ") {
+		t.Errorf("AddMarker returned an unexpected body: %q", body)
+	}
+
+	parsed, ok := ParseMarker(body)
+	if !ok {
+		t.Fatalf("ParseMarker did not find the marker in %q", body)
+	}
+	if parsed != marker {
+		t.Errorf("ParseMarker returned %+v, expected %+v", parsed, marker)
+	}
+
+	// Adding a marker again replaces the previous one
+	marker.RunID = "other-run"
+	body = AddMarker(body, marker)
+	if strings.Count(body, "gh-migrate-releases:") != 1 {
+		t.Errorf("AddMarker did not replace the existing marker: %q", body)
+	}
+	if parsed, _ := ParseMarker(body); parsed.RunID != "other-run" {
+		t.Errorf("ParseMarker returned run %q, expected other-run", parsed.RunID)
+	}
+}
+
+func TestMarkerCreatedTag(t *testing.T) {
+	marker := Marker{SourceHost: "github.com", SourceRepository: "source-org/repo", SourceReleaseID: 1, RunID: "run"}
+
+	// Markers written before the tag was recorded parse as not having created it
+	body := AddMarker("", marker)
+	if strings.Contains(body, "created_tag") {
+		t.Errorf("AddMarker recorded an uncreated tag: %q", body)
+	}
+
+	marker.CreatedTag = true
+	parsed, ok := ParseMarker(AddMarker("", marker))
+	if !ok || !parsed.CreatedTag {
+		t.Errorf("ParseMarker returned %+v, expected the tag to be recorded as created", parsed)
+	}
+}
+
+func TestParseMarkerWithoutMarker(t *testing.T) {
+	if _, ok := ParseMarker("Release notes <!-- a comment -->"); ok {
+		t.Errorf("ParseMarker found a marker in a body without one")
+	}
+}
+
+func TestMarkerMatches(t *testing.T) {
+	marker := Marker{SourceHost: "github.example.com", SourceRepository: "source-org/repo", RunID: "run-1"}
+
+	if !marker.Matches("GitHub.example.com", "Source-Org/repo", "") {
+		t.Errorf("Matches returned false for the same source")
+	}
+	if !marker.Matches("github.example.com", "source-org/repo", "run-1") {
+		t.Errorf("Matches returned false for the same run")
+	}
+	if marker.Matches("github.example.com", "source-org/repo", "run-2") {
+		t.Errorf("Matches returned true for another run")
+	}
+	if marker.Matches("github.example.com", "source-org/other", "") {
+		t.Errorf("Matches returned true for another repository")
+	}
+}
@@ -0,0 +1,49 @@
+package releases
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// NewCreateRequest builds the request creating the target counterpart of a source release. Only fields
+// the create endpoint accepts are set; release notes are never regenerated as the body is carried over.
+// The release is marked as latest only when latest is set, and linked to a new discussion when
+// discussionCategory is not empty.
+func NewCreateRequest(source *github.RepositoryRelease, body string, latest bool, discussionCategory string) *github.RepositoryRelease {
+	makeLatest := "false"
+	if latest && !source.GetDraft() && !source.GetPrerelease() {
+		makeLatest = "true"
+	}
+
+	request := &github.RepositoryRelease{
+		TagName:              github.String(source.GetTagName()),
+		Name:                 github.String(source.GetName()),
+		Body:                 github.String(body),
+		Draft:                github.Bool(source.GetDraft()),
+		Prerelease:           github.Bool(source.GetPrerelease()),
+		MakeLatest:           github.String(makeLatest),
+		GenerateReleaseNotes: github.Bool(false),
+		// Only sent by targets that can set the release date, GitHub sets it on publication
+		PublishedAt: source.PublishedAt,
+	}
+	if source.GetTargetCommitish() != "" {
+		request.TargetCommitish = github.String(source.GetTargetCommitish())
+	}
+	if discussionCategory != "" {
+		request.DiscussionCategoryName = github.String(discussionCategory)
+	}
+
+	return request
+}
+
+// MatchDiscussionCategory returns the target category with the same name as a source category, ignoring case,
+// or the fallback category when there is none.
+func MatchDiscussionCategory(source string, target []string, fallback string) string {
+	for _, category := range target {
+		if strings.EqualFold(category, source) {
+			return category
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,70 @@
+package releases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestNewCreateRequest(t *testing.T) {
+	source := &github.RepositoryRelease{
+		ID:              github.Int64(1),
+		TagName:         github.String("v1.0.0"),
+		Name:            github.String("Version 1"),
+		Body:            github.String("Source body"),
+		TargetCommitish: github.String("main"),
+		Prerelease:      github.Bool(false),
+		PublishedAt:     &github.Timestamp{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		UploadURL:       github.String("https://uploads.example.com"),
+		Assets:          []*github.ReleaseAsset{{Name: github.String("app.zip")}},
+	}
+
+	request := NewCreateRequest(source, "Mapped body", true, "Announcements")
+
+	if request.GetTagName() != "v1.0.0" || request.GetName() != "Version 1" || request.GetTargetCommitish() != "main" {
+		t.Errorf("NewCreateRequest did not copy the release identity: %+v", request)
+	}
+	if request.GetBody() != "Mapped body" {
+		t.Errorf("NewCreateRequest body = %q, expected the mapped body", request.GetBody())
+	}
+	if request.GetMakeLatest() != "true" {
+		t.Errorf("NewCreateRequest make_latest = %q, expected true", request.GetMakeLatest())
+	}
+	if request.GetDiscussionCategoryName() != "Announcements" {
+		t.Errorf("NewCreateRequest discussion category = %q, expected Announcements", request.GetDiscussionCategoryName())
+	}
+	if !request.GetPublishedAt().Equal(source.GetPublishedAt()) {
+		t.Errorf("NewCreateRequest published_at = %v, expected the source publication date", request.GetPublishedAt())
+	}
+	if request.GetGenerateReleaseNotes() {
+		t.Errorf("NewCreateRequest asked to generate release notes")
+	}
+	if request.ID != nil || request.UploadURL != nil || request.Assets != nil {
+		t.Errorf("NewCreateRequest copied read-only fields: %+v", request)
+	}
+
+	// Prereleases can't be the latest release
+	source.Prerelease = github.Bool(true)
+	request = NewCreateRequest(source, "", true, "")
+	if request.GetMakeLatest() != "false" || !request.GetPrerelease() {
+		t.Errorf("NewCreateRequest for a prerelease: make_latest = %q, prerelease = %t", request.GetMakeLatest(), request.GetPrerelease())
+	}
+	if request.DiscussionCategoryName != nil {
+		t.Errorf("NewCreateRequest set a discussion category without one")
+	}
+}
+
+func TestMatchDiscussionCategory(t *testing.T) {
+	target := []string{"Announcements", "General"}
+
+	if category := MatchDiscussionCategory("general", target, ""); category != "General" {
+		t.Errorf("MatchDiscussionCategory returned %q, expected General", category)
+	}
+	if category := MatchDiscussionCategory("Releases", target, "Announcements"); category != "Announcements" {
+		t.Errorf("MatchDiscussionCategory returned %q, expected the fallback", category)
+	}
+	if category := MatchDiscussionCategory("Releases", target, ""); category != "" {
+		t.Errorf("MatchDiscussionCategory returned %q, expected none", category)
+	}
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/spf13/viper"
+)
+
+// Filter selects repositories of an organization.
+type Filter struct {
+	// Visibility is "all", "public", "private" or "internal"
+	Visibility string
+	// Archived is "include", "exclude" or "only"
+	Archived string
+	// Topics the repository must all have
+	Topics []string
+	// NamePattern the repository name must match, if set
+	NamePattern *regexp.Regexp
+}
+
+// NewFilter creates a filter from the configured discovery options.
+func NewFilter() (Filter, error) {
+	filter := Filter{
+		Visibility: strings.ToLower(viper.GetString("VISIBILITY")),
+		Archived:   strings.ToLower(viper.GetString("ARCHIVED")),
+	}
+
+	switch filter.Visibility {
+	case "":
+		filter.Visibility = "all"
+	case "all", "public", "private", "internal":
+	default:
+		return filter, fmt.Errorf("invalid visibility %q: expected all, public, private or internal", filter.Visibility)
+	}
+
+	switch filter.Archived {
+	case "":
+		filter.Archived = "include"
+	case "include", "exclude", "only":
+	default:
+		return filter, fmt.Errorf("invalid archived filter %q: expected include, exclude or only", filter.Archived)
+	}
+
+	for _, topic := range strings.Split(viper.GetString("TOPICS"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			filter.Topics = append(filter.Topics, strings.ToLower(topic))
+		}
+	}
+
+	if viper.GetString("NAME_PATTERN") != "" {
+		pattern, err := regexp.Compile(viper.GetString("NAME_PATTERN"))
+		if err != nil {
+			return filter, fmt.Errorf("invalid name pattern: %v", err)
+		}
+		filter.NamePattern = pattern
+	}
+
+	return filter, nil
+}
+
+// Matches returns whether a repository is selected by the filter.
+func (f Filter) Matches(repository *github.Repository) bool {
+	if f.Visibility != "all" && !strings.EqualFold(repository.GetVisibility(), f.Visibility) {
+		return false
+	}
+
+	if (f.Archived == "exclude" && repository.GetArchived()) || (f.Archived == "only" && !repository.GetArchived()) {
+		return false
+	}
+
+	topics := make(map[string]bool)
+	for _, topic := range repository.Topics {
+		topics[strings.ToLower(topic)] = true
+	}
+	for _, topic := range f.Topics {
+		if !topics[topic] {
+			return false
+		}
+	}
+
+	if f.NamePattern != nil && !f.NamePattern.MatchString(repository.GetName()) {
+		return false
+	}
+
+	return true
+}
+
+// Discover returns the "owner/name" of every repository of the source organization selected by the
+// configured filters, optionally only those with at least one release.
+func Discover() ([]string, error) {
+	filter, err := NewFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	organization := viper.GetString("SOURCE_ORGANIZATION")
+	if organization == "" {
+		return nil, fmt.Errorf("source organization is required to discover repositories")
+	}
+
+	orgRepositories, err := api.GetSourceOrganizationRepositories(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories []string
+	for _, repository := range orgRepositories {
+		if !filter.Matches(repository) {
+			continue
+		}
+
+		if viper.GetBool("WITH_RELEASES") {
+			hasReleases, err := api.HasSourceReleases(organization, repository.GetName())
+			if err != nil {
+				return nil, err
+			}
+			if !hasReleases {
+				continue
+			}
+		}
+
+		repositories = append(repositories, organization+"/"+repository.GetName())
+	}
+
+	return repositories, nil
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/files"
+	"github.com/spf13/viper"
+)
+
+// List returns the repositories to work on, from either the source organization, the repository list file
+// or the single repository option.
+func List() ([]string, error) {
+	if viper.GetBool("ALL_REPOSITORIES") {
+		return Discover()
+	} else if viper.GetString("REPOSITORY_LIST") != "" {
+		// Read repository list from file
+		return files.ReadRepositoryListFromFile(viper.GetString("REPOSITORY_LIST"))
+	} else if viper.GetString("REPOSITORY") != "" {
+		return []string{viper.GetString("REPOSITORY")}, nil
+	}
+
+	return nil, errors.New("no repository, repository list or all repositories option specified")
+}
+
+// Split returns the owner and name of a repository, defaulting the owner to the source organization
+// when the repository does not include one.
+func Split(repository string) (string, string) {
+	// if repository includes owner, split it
+	if strings.Contains(repository, "/") {
+		repositoryParts := strings.Split(repository, "/")
+		return repositoryParts[0], repositoryParts[1]
+	}
+
+	return viper.GetString("SOURCE_ORGANIZATION"), repository
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/spf13/viper"
+)
+
+func TestSplit(t *testing.T) {
+	viper.Set("SOURCE_ORGANIZATION", "source-org")
+
+	owner, name := Split("other-org/repo")
+	if owner != "other-org" || name != "repo" {
+		t.Errorf("Split returned %s/%s, expected other-org/repo", owner, name)
+	}
+
+	owner, name = Split("repo")
+	if owner != "source-org" || name != "repo" {
+		t.Errorf("Split returned %s/%s, expected source-org/repo", owner, name)
+	}
+}
+
+func TestList(t *testing.T) {
+	fileName := "repositories.txt"
+
+	err := os.WriteFile(fileName, []byte("https://github.example.com/owner/repo-name\nowner/repo-name2\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer os.Remove(fileName)
+
+	viper.Set("REPOSITORY", "")
+	viper.Set("REPOSITORY_LIST", fileName)
+
+	repositories, err := List()
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	expected := []string{"owner/repo-name", "owner/repo-name2"}
+	if !reflect.DeepEqual(repositories, expected) {
+		t.Errorf("List returned %v, expected %v", repositories, expected)
+	}
+
+	viper.Set("REPOSITORY_LIST", "")
+	if _, err := List(); err == nil {
+		t.Errorf("List did not return an error when no repository was specified")
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	viper.Set("VISIBILITY", "private")
+	viper.Set("ARCHIVED", "exclude")
+	viper.Set("TOPICS", "go, Migrate")
+	viper.Set("NAME_PATTERN", "^service-")
+	defer func() {
+		viper.Set("VISIBILITY", "")
+		viper.Set("ARCHIVED", "")
+		viper.Set("TOPICS", "")
+		viper.Set("NAME_PATTERN", "")
+	}()
+
+	filter, err := NewFilter()
+	if err != nil {
+		t.Fatalf("NewFilter returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		repository *github.Repository
+		expected   bool
+	}{
+		{
+			name:       "matching repository",
+			repository: &github.Repository{Name: github.String("service-a"), Visibility: github.String("private"), Topics: []string{"migrate", "go", "api"}},
+			expected:   true,
+		},
+		{
+			name:       "wrong visibility",
+			repository: &github.Repository{Name: github.String("service-a"), Visibility: github.String("public"), Topics: []string{"migrate", "go"}},
+			expected:   false,
+		},
+		{
+			name:       "archived",
+			repository: &github.Repository{Name: github.String("service-a"), Visibility: github.String("private"), Archived: github.Bool(true), Topics: []string{"migrate", "go"}},
+			expected:   false,
+		},
+		{
+			name:       "missing topic",
+			repository: &github.Repository{Name: github.String("service-a"), Visibility: github.String("private"), Topics: []string{"go"}},
+			expected:   false,
+		},
+		{
+			name:       "name not matching",
+			repository: &github.Repository{Name: github.String("library-a"), Visibility: github.String("private"), Topics: []string{"migrate", "go"}},
+			expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := filter.Matches(test.repository); actual != test.expected {
+				t.Errorf("Matches returned %v, expected %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewFilterErrors(t *testing.T) {
+	defer func() {
+		viper.Set("VISIBILITY", "")
+		viper.Set("ARCHIVED", "")
+		viper.Set("NAME_PATTERN", "")
+	}()
+
+	viper.Set("VISIBILITY", "secret")
+	if _, err := NewFilter(); err == nil {
+		t.Errorf("NewFilter did not return an error for an invalid visibility")
+	}
+	viper.Set("VISIBILITY", "")
+
+	viper.Set("ARCHIVED", "sometimes")
+	if _, err := NewFilter(); err == nil {
+		t.Errorf("NewFilter did not return an error for an invalid archived filter")
+	}
+	viper.Set("ARCHIVED", "")
+
+	viper.Set("NAME_PATTERN", "[")
+	if _, err := NewFilter(); err == nil {
+		t.Errorf("NewFilter did not return an error for an invalid name pattern")
+	}
+}
@@ -0,0 +1,176 @@
+package changelog
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/mapping"
+	"github.com/mona-actions/gh-migrate-releases/internal/releases"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+func GenerateChangelog() {
+	checkVars()
+
+	owner, repository := repositories.Split(viper.GetString("REPOSITORY"))
+	logger := slog.With("repository", owner+"/"+repository)
+
+	// Load handle mapping, used for release authors and mentions in release notes
+	var handles mapping.HandleMap
+	if viper.GetString("MAPPING_FILE") != "" {
+		var err error
+		handles, err = mapping.LoadHandleMap(viper.GetString("MAPPING_FILE"))
+		if err != nil {
+			slog.Error("Unable to read mapping file", "file", viper.GetString("MAPPING_FILE"), "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Links are only rewritten when there is a target organization to point them to
+	var links *mapping.LinkRewriter
+	if viper.GetString("TARGET_ORGANIZATION") != "" {
+		var numbers mapping.NumberMap
+		if viper.GetString("NUMBER_MAPPING_FILE") != "" {
+			var err error
+			numbers, err = mapping.LoadNumberMap(viper.GetString("NUMBER_MAPPING_FILE"))
+			if err != nil {
+				slog.Error("Unable to read number mapping file", "file", viper.GetString("NUMBER_MAPPING_FILE"), "error", err)
+				os.Exit(1)
+			}
+		}
+		links = mapping.NewLinkRewriter(owner, repository, numbers)
+	}
+
+	changelogTemplate := releases.DefaultChangelogTemplate
+	if viper.GetString("TEMPLATE") != "" {
+		content, err := os.ReadFile(viper.GetString("TEMPLATE"))
+		if err != nil {
+			slog.Error("Unable to read changelog template", "file", viper.GetString("TEMPLATE"), "error", err)
+			os.Exit(1)
+		}
+		changelogTemplate = string(content)
+	}
+	changelog, err := releases.NewChangelog(changelogTemplate, viper.GetString("TIME_ZONE"))
+	if err != nil {
+		slog.Error("Invalid changelog template", "error", err)
+		os.Exit(1)
+	}
+
+	fetchReleasesSpinner, _ := pterm.DefaultSpinner.Start("Fetching releases from repository: ", owner+"/"+repository)
+	sourceReleases, err := api.GetSourceRepositoryReleases(owner, repository)
+	if err != nil {
+		fetchReleasesSpinner.Fail()
+		logger.Error("Unable to get releases", api.ErrorAttrs(err)...)
+		os.Exit(1)
+	}
+	fetchReleasesSpinner.Success("Releases fetched: ", len(sourceReleases))
+
+	data := releases.ChangelogData{Repository: owner + "/" + repository}
+	if links != nil {
+		data.Repository = viper.GetString("TARGET_ORGANIZATION") + "/" + repository
+	}
+	for _, release := range sourceReleases {
+		// Drafts were never released, and prereleases are optional
+		if release.GetDraft() || (release.GetPrerelease() && viper.GetBool("SKIP_PRERELEASES")) {
+			continue
+		}
+		data.Releases = append(data.Releases, newEntry(release, handles, links))
+	}
+
+	err = releases.SortEntries(data.Releases, viper.GetString("ORDER"))
+	if err != nil {
+		slog.Error("Invalid changelog order", "error", err)
+		os.Exit(1)
+	}
+
+	content, err := changelog.Render(data)
+	if err != nil {
+		slog.Error("Unable to render changelog", "error", err)
+		os.Exit(1)
+	}
+
+	outputFile := viper.GetString("OUTPUT_FILE")
+	err = os.WriteFile(outputFile, []byte(content), 0644)
+	if err != nil {
+		slog.Error("Unable to write changelog", "file", outputFile, "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Changelog written", "file", outputFile, "releases", len(data.Releases))
+
+	if !viper.GetBool("COMMIT") {
+		return
+	}
+
+	// Commit the changelog at the same relative path in the target repository
+	path := filepath.ToSlash(filepath.Clean(outputFile))
+	commitSpinner, _ := pterm.DefaultSpinner.Start("Committing changelog to target repository: ", data.Repository)
+	commitURL, err := api.CommitTargetFile(repository, path, viper.GetString("BRANCH"), viper.GetString("COMMIT_MESSAGE"), []byte(content))
+	if err != nil {
+		commitSpinner.Fail()
+		logger.Error("Unable to commit changelog", append([]any{"path", path}, api.ErrorAttrs(err)...)...)
+		os.Exit(1)
+	}
+	if commitURL == "" {
+		commitSpinner.Success("Changelog is already up to date in ", data.Repository)
+		return
+	}
+	commitSpinner.Success("Changelog committed: ", commitURL)
+}
+
+func checkVars() {
+	outputFile := filepath.Clean(viper.GetString("OUTPUT_FILE"))
+	if viper.GetBool("COMMIT") && (filepath.IsAbs(outputFile) || strings.HasPrefix(outputFile, "..")) {
+		slog.Error("Output file must be a relative path inside the working directory when committing it")
+		os.Exit(1)
+	}
+
+	if viper.GetBool("COMMIT") && viper.GetString("TARGET_ORGANIZATION") == "" {
+		slog.Error("Target organization is required when committing the changelog")
+		os.Exit(1)
+	} else if !strings.Contains(viper.GetString("REPOSITORY"), "/") && viper.GetString("SOURCE_ORGANIZATION") == "" {
+		slog.Error("Source organization is required when the repository does not include its owner")
+		os.Exit(1)
+	}
+	// check that the order is valid before fetching anything
+	if err := releases.SortEntries(nil, viper.GetString("ORDER")); err != nil {
+		slog.Error("Invalid changelog order", "error", err)
+		os.Exit(1)
+	}
+}
+
+// newEntry maps a source release to a changelog entry, pointing it to the target repository when links are rewritten.
+func newEntry(release *github.RepositoryRelease, handles mapping.HandleMap, links *mapping.LinkRewriter) releases.ChangelogEntry {
+	author, mapped := handles.Target(release.GetAuthor().GetLogin())
+	entry := releases.ChangelogEntry{
+		Name:         release.GetName(),
+		Tag:          release.GetTagName(),
+		Author:       author,
+		SourceAuthor: release.GetAuthor().GetLogin(),
+		Mapped:       mapped,
+		URL:          release.GetHTMLURL(),
+		Body:         strings.TrimSpace(*mapping.ModifyReleaseBody(release.Body, handles, links)),
+		Prerelease:   release.GetPrerelease(),
+	}
+	if entry.Name == "" {
+		entry.Name = entry.Tag
+	}
+	if links != nil {
+		entry.URL = fmt.Sprintf("https://%s/%s/%s/releases/tag/%s", links.TargetHost, links.TargetOwner, links.Repository, url.PathEscape(release.GetTagName()))
+	}
+	if release.CreatedAt != nil {
+		entry.CreatedAt = &release.CreatedAt.Time
+	}
+	if release.PublishedAt != nil {
+		entry.PublishedAt = &release.PublishedAt.Time
+	}
+
+	return entry
+}
@@ -2,6 +2,8 @@ package export
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/mona-actions/gh-migrate-releases/internal/api"
 	"github.com/mona-actions/gh-migrate-releases/internal/files"
@@ -14,16 +16,20 @@ func CreateJSONs() {
 	fetchReleasesSpinner, _ := pterm.DefaultSpinner.Start("Fetching releases from repository...")
 	repository := viper.GetString("REPOSITORY")
 	owner := viper.GetString("SOURCE_ORGANIZATION")
+	logger := slog.With("repository", owner+"/"+repository)
 	releases, err := api.GetSourceRepositoryReleases(owner, repository)
 	if err != nil {
-		pterm.Fatal.Printf("Error getting releases: %v", err)
+		fetchReleasesSpinner.Fail()
+		logger.Error("Unable to get releases", api.ErrorAttrs(err)...)
+		os.Exit(1)
 	}
 	for index, release := range releases {
 		filename := "release-" + fmt.Sprint(index) + ".json"
 		err := files.CreateJSON(release, filename)
 		if err != nil {
-			pterm.Fatal.Printf("Error creating JSON: %v", err)
 			fetchReleasesSpinner.Fail()
+			logger.Error("Unable to create JSON", "release", release.GetTagName(), "file", filename, "error", err)
+			os.Exit(1)
 		}
 		fetchReleasesSpinner.Success()
 	}
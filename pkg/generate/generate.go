@@ -0,0 +1,129 @@
+package generate
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/files"
+	"github.com/mona-actions/gh-migrate-releases/internal/mapping"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// Confidence levels written to the draft mapping, from most to least reliable
+const (
+	confidenceHigh   = "high"   // source email matches a SAML identity in the target organization
+	confidenceMedium = "medium" // source email matches the public email of a target organization member
+	confidenceNone   = "none"   // no match, the target handle must be filled in by hand
+)
+
+func GenerateMapping() {
+	repositoryList, err := repositories.List()
+	if err != nil {
+		slog.Error("Unable to list repositories", "error", err)
+		os.Exit(1)
+	}
+
+	// Collect every release author and @mention in the source repositories
+	scanSpinner, _ := pterm.DefaultSpinner.Start("Scanning releases in source repositories...")
+	var handles []string
+	seen := make(map[string]bool)
+	addHandle := func(handle string) {
+		if handle != "" && !seen[strings.ToLower(handle)] {
+			seen[strings.ToLower(handle)] = true
+			handles = append(handles, handle)
+		}
+	}
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+		scanSpinner.UpdateText("Scanning releases in repository: " + owner + "/" + name)
+
+		releases, err := api.GetSourceRepositoryReleases(owner, name)
+		if err != nil {
+			scanSpinner.Fail()
+			slog.Error("Unable to get releases", append([]any{"repository", owner + "/" + name}, api.ErrorAttrs(err)...)...)
+			os.Exit(1)
+		}
+
+		for _, release := range releases {
+			addHandle(release.GetAuthor().GetLogin())
+			for _, handle := range mapping.ExtractMentions(release.GetBody()) {
+				addHandle(handle)
+			}
+		}
+	}
+	sort.Slice(handles, func(i, j int) bool { return strings.ToLower(handles[i]) < strings.ToLower(handles[j]) })
+	scanSpinner.Success(fmt.Sprintf("%d handles found in %d repositories", len(handles), len(repositoryList)))
+
+	// Index the target organization members by SAML identity and public email
+	// Without SAML identities, handles can still be matched through public emails with a lower confidence
+	identitiesSpinner, _ := pterm.DefaultSpinner.Start("Fetching target organization identities...")
+	identities, err := api.GetTargetOrganizationIdentities(viper.GetString("TARGET_ORGANIZATION"))
+	if err != nil {
+		identitiesSpinner.Warning(fmt.Sprintf("%d target organization members fetched without SAML identities", len(identities)))
+		slog.Warn("Unable to get target identities, handles are only matched through public emails", api.ErrorAttrs(err)...)
+	}
+	samlIndex := make(map[string]string)
+	emailIndex := make(map[string]string)
+	for _, identity := range identities {
+		if identity.SAMLNameID != "" {
+			samlIndex[strings.ToLower(identity.SAMLNameID)] = identity.Login
+		}
+		for _, email := range identity.SAMLEmails {
+			samlIndex[strings.ToLower(email)] = identity.Login
+		}
+		if identity.Email != "" {
+			emailIndex[strings.ToLower(identity.Email)] = identity.Login
+		}
+	}
+	if err == nil {
+		identitiesSpinner.Success(fmt.Sprintf("%d target organization members fetched", len(identities)))
+	}
+
+	// Match each source handle to a target user through its email
+	matchSpinner, _ := pterm.DefaultSpinner.Start("Matching source handles to target users...")
+	records := [][]string{{"source", "target", "confidence"}}
+	var matched, unchanged int
+	for _, handle := range handles {
+		matchSpinner.UpdateText("Matching handle: " + handle)
+
+		user, err := api.GetSourceUser(handle)
+		if err != nil {
+			slog.Warn("Unable to get source user", append([]any{"handle", handle}, api.ErrorAttrs(err)...)...)
+		}
+
+		target, confidence := "", confidenceNone
+		if email := strings.ToLower(user.GetEmail()); email != "" {
+			if login, ok := samlIndex[email]; ok {
+				target, confidence = login, confidenceHigh
+			} else if login, ok := emailIndex[email]; ok {
+				target, confidence = login, confidenceMedium
+			}
+		}
+
+		// Handles that are the same in the target don't need a mapping
+		if strings.EqualFold(target, handle) {
+			unchanged++
+			continue
+		}
+		if target != "" {
+			matched++
+		}
+
+		records = append(records, []string{handle, target, confidence})
+	}
+	matchSpinner.Success(fmt.Sprintf("%d handles matched, %d unchanged, %d need review", matched, unchanged, len(records)-1-matched))
+
+	err = files.CreateCSV(records, viper.GetString("OUTPUT_FILE"))
+	if err != nil {
+		slog.Error("Unable to write mapping file", "file", viper.GetString("OUTPUT_FILE"), "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Draft mapping written, review it and fill in blank targets before using it with sync", "file", viper.GetString("OUTPUT_FILE"))
+}
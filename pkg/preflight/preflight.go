@@ -0,0 +1,230 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// minRateLimitRemaining is the number of API requests each repository is expected to need at least
+const minRateLimitRemaining = 50
+
+// Check is the result of a single pre-flight check. A passed check can carry a warning that doesn't
+// stop the sync, such as an expected wait for the rate limit to reset.
+type Check struct {
+	Name    string
+	Subject string
+	Passed  bool
+	Warning bool
+	Details string
+}
+
+func Preflight() {
+	repositoryList, err := repositories.List()
+	if err != nil {
+		pterm.Error.Printf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	if !RunChecks(repositoryList) {
+		os.Exit(1)
+	}
+}
+
+// RunChecks runs and prints every pre-flight check for the repositories, and returns whether they all passed.
+func RunChecks(repositoryList []string) bool {
+	preflightSpinner, _ := pterm.DefaultSpinner.Start("Running pre-flight checks...")
+	checks := Checks(repositoryList)
+
+	passed, warnings := true, false
+	for _, check := range checks {
+		passed = passed && check.Passed
+		warnings = warnings || check.Warning
+	}
+
+	if passed && warnings {
+		preflightSpinner.Warning("All pre-flight checks passed, with warnings")
+	} else if passed {
+		preflightSpinner.Success("All pre-flight checks passed!")
+	} else {
+		preflightSpinner.Fail("Some pre-flight checks failed")
+	}
+	PrintChecks(checks)
+
+	return passed
+}
+
+// Checks verifies the source and target credentials, access to every repository and rate limit headroom.
+func Checks(repositoryList []string) []Check {
+	var checks []Check
+
+	sourceHost := viper.GetString("SOURCE_HOSTNAME")
+	if sourceHost == "" {
+		sourceHost = "github.com"
+	}
+	// Only the target needs write access, the source repositories are checked for read access below
+	checks = append(checks, checkToken("Source token", sourceHost, api.GetSourceTokenInfo, "", len(repositoryList))...)
+	if api.TargetType() == "github" {
+		checks = append(checks, checkToken("Target token", "github.com", api.GetTargetTokenInfo, "repo", len(repositoryList))...)
+		for _, repository := range repositoryList {
+			owner, name := repositories.Split(repository)
+			checks = append(checks, checkRepositories(owner, name)...)
+		}
+		return checks
+	}
+
+	// Other target platforms are only checked for credentials and the existence of each repository
+	target, err := api.NewTarget()
+	if err != nil {
+		checks = append(checks, Check{Name: "Target token", Subject: api.TargetType(), Passed: false, Details: err.Error()})
+	}
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+		checks = append(checks, checkSourceRepository(owner, name))
+		if target != nil {
+			checks = append(checks, checkTargetRepository(target, name))
+		}
+	}
+
+	return checks
+}
+
+func checkToken(name string, host string, getTokenInfo func() (*api.TokenInfo, error), requiredScope string, repositoryCount int) []Check {
+	info, err := getTokenInfo()
+	if err != nil {
+		return []Check{{Name: name, Subject: host, Passed: false, Details: err.Error()}}
+	}
+
+	tokenCheck := Check{Name: name, Subject: host, Passed: true}
+	switch {
+	case info.Login == "":
+		tokenCheck.Details = "GitHub App installation"
+	case info.Scopes == nil:
+		tokenCheck.Details = fmt.Sprintf("%s, fine-grained token", info.Login)
+	case requiredScope != "" && !hasScope(info.Scopes, requiredScope):
+		tokenCheck.Passed = false
+		tokenCheck.Details = fmt.Sprintf("%s, missing %s scope (has: %s)", info.Login, requiredScope, strings.Join(info.Scopes, ", "))
+	default:
+		tokenCheck.Details = fmt.Sprintf("%s, scopes: %s", info.Login, strings.Join(info.Scopes, ", "))
+	}
+
+	// Clients sleep until the rate limit resets, so a low rate limit only makes the sync wait
+	rateLimitCheck := Check{Name: "Rate limit", Subject: host, Passed: true}
+	if info.RateLimit == nil {
+		rateLimitCheck.Details = "rate limiting disabled"
+	} else {
+		needed := minRateLimitRemaining * repositoryCount
+		rateLimitCheck.Details = fmt.Sprintf("%d of %d remaining, %d needed, resets at %s",
+			info.RateLimit.Remaining, info.RateLimit.Limit, needed, info.RateLimit.Reset.Format("15:04:05"))
+		if info.RateLimit.Remaining < needed {
+			rateLimitCheck.Warning = true
+			rateLimitCheck.Details += fmt.Sprintf(", expect to wait about %s for the rate limit to reset",
+				rateLimitWait(info.RateLimit, needed).Round(time.Minute))
+		}
+	}
+
+	return []Check{tokenCheck, rateLimitCheck}
+}
+
+// rateLimitWait estimates how long a sync needing the given number of requests waits for rate limit resets,
+// until the current reset and then an hour for every further limit's worth of requests.
+func rateLimitWait(rate *github.Rate, needed int) time.Duration {
+	wait := time.Until(rate.Reset.Time)
+	if wait < 0 {
+		wait = 0
+	}
+	if rate.Limit > 0 {
+		shortfall := needed - rate.Remaining - rate.Limit
+		if shortfall > 0 {
+			wait += time.Duration((shortfall+rate.Limit-1)/rate.Limit) * time.Hour
+		}
+	}
+	return wait
+}
+
+func checkSourceRepository(owner string, name string) Check {
+	sourceCheck := Check{Name: "Source repository", Subject: owner + "/" + name, Passed: true, Details: "readable"}
+	sourceRepository, err := api.GetSourceRepository(owner, name)
+	if err != nil {
+		sourceCheck.Passed, sourceCheck.Details = false, err.Error()
+	} else if sourceRepository == nil {
+		sourceCheck.Passed, sourceCheck.Details = false, "not found or not readable with the source token"
+	}
+
+	return sourceCheck
+}
+
+// checkTargetRepository checks that a repository exists on a target platform other than GitHub.
+func checkTargetRepository(target api.Target, name string) Check {
+	targetCheck := Check{Name: "Target repository", Subject: viper.GetString("TARGET_ORGANIZATION") + "/" + name, Passed: true, Details: "exists"}
+	exists, err := target.RepositoryExists(name)
+	if err != nil {
+		targetCheck.Passed, targetCheck.Details = false, err.Error()
+	} else if !exists {
+		targetCheck.Passed, targetCheck.Details = false, "not found or not readable with the target token"
+	}
+
+	return targetCheck
+}
+
+func checkRepositories(owner string, name string) []Check {
+	sourceCheck := checkSourceRepository(owner, name)
+
+	targetOrganization := viper.GetString("TARGET_ORGANIZATION")
+	targetCheck := Check{Name: "Target repository", Subject: targetOrganization + "/" + name, Passed: true, Details: "writable"}
+	targetRepository, err := api.GetTargetRepository(name)
+	switch {
+	case err != nil:
+		targetCheck.Passed, targetCheck.Details = false, err.Error()
+	case targetRepository == nil:
+		targetCheck.Passed, targetCheck.Details = false, "not found or not readable with the target token"
+	case targetRepository.GetArchived() && viper.GetBool("UNARCHIVE_TEMPORARILY") && targetRepository.Permissions == nil:
+		targetCheck.Details = "archived, will be unarchived for the sync if the GitHub App has the Administration write permission"
+	case targetRepository.GetArchived() && viper.GetBool("UNARCHIVE_TEMPORARILY") && !targetRepository.GetPermissions()["admin"]:
+		targetCheck.Passed, targetCheck.Details = false, "archived, unarchiving it needs admin access with the target token"
+	case targetRepository.GetArchived() && viper.GetBool("UNARCHIVE_TEMPORARILY"):
+		targetCheck.Details = "archived, will be unarchived for the sync"
+	case targetRepository.GetArchived():
+		targetCheck.Passed, targetCheck.Details = false, "archived, use --unarchive-temporarily to sync it"
+	case targetRepository.Permissions == nil:
+		// Installation tokens don't report permissions, the app's repository permissions apply
+		targetCheck.Details = "exists, permissions not reported for GitHub App installations"
+	case !targetRepository.GetPermissions()["push"]:
+		targetCheck.Passed, targetCheck.Details = false, "no write access with the target token"
+	}
+
+	return []Check{sourceCheck, targetCheck}
+}
+
+// PrintChecks prints the pre-flight checks as a pass/fail table.
+func PrintChecks(checks []Check) {
+	tableData := pterm.TableData{{"Check", "Subject", "Result", "Details"}}
+	for _, check := range checks {
+		result := pterm.Green("pass")
+		if !check.Passed {
+			result = pterm.Red("fail")
+		} else if check.Warning {
+			result = pterm.Yellow("warn")
+		}
+		tableData = append(tableData, []string{check.Name, check.Subject, result, check.Details})
+	}
+
+	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
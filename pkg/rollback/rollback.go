@@ -0,0 +1,162 @@
+package rollback
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/releases"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// candidate is a target release created by a sync that matches the rollback selection.
+type candidate struct {
+	repository string
+	release    *github.RepositoryRelease
+	marker     releases.Marker
+}
+
+func Rollback() {
+	// Releases are deleted through the GitHub API, other target platforms aren't supported
+	if api.TargetType() != "github" {
+		pterm.Error.Println("Error: rollback only supports GitHub targets, got --target-type " + api.TargetType())
+		os.Exit(1)
+	}
+
+	repositoryList, err := repositories.List()
+	if err != nil {
+		pterm.Error.Printf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	sourceHost := strings.TrimSuffix(viper.GetString("SOURCE_HOSTNAME"), "/")
+	if sourceHost == "" {
+		sourceHost = "github.com"
+	}
+	runID := viper.GetString("RUN_ID")
+
+	// Find the releases created from the selected source repositories, and run when given
+	var candidates []candidate
+	var totalErrors int
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+
+		findSpinner, _ := pterm.DefaultSpinner.Start("Finding synced releases in repository: ", name)
+		targetReleases, err := api.GetTargetRepositoryReleases(name)
+		if err != nil {
+			totalErrors++
+			findSpinner.Fail(fmt.Sprintf("Error listing releases of %s/%s: %v", viper.GetString("TARGET_ORGANIZATION"), name, err))
+			continue
+		}
+
+		var found int
+		for _, release := range targetReleases {
+			marker, ok := releases.ParseMarker(release.GetBody())
+			if !ok || !marker.Matches(sourceHost, owner+"/"+name, runID) {
+				continue
+			}
+			candidates = append(candidates, candidate{repository: name, release: release, marker: marker})
+			found++
+		}
+		findSpinner.Success(fmt.Sprintf("%s/%s: %d synced releases found", viper.GetString("TARGET_ORGANIZATION"), name, found))
+	}
+
+	if len(candidates) == 0 {
+		pterm.Info.Println("No releases to roll back")
+		exitOnErrors(totalErrors)
+		return
+	}
+
+	// Only tags created by the sync are deleted, the others came over with the git history
+	var createdTags int
+	tableData := pterm.TableData{{"Repository", "Tag", "Name", "Source Release", "Run ID", "Tag Created"}}
+	for _, c := range candidates {
+		tagCreated := "no"
+		if c.marker.CreatedTag {
+			tagCreated = "yes"
+			createdTags++
+		}
+		tableData = append(tableData, []string{c.repository, c.release.GetTagName(), c.release.GetName(),
+			c.marker.SourceRepository + "@" + strconv.FormatInt(c.marker.SourceReleaseID, 10), c.marker.RunID, tagCreated})
+	}
+	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+
+	action := "releases"
+	if viper.GetBool("DELETE_TAGS") {
+		action = fmt.Sprintf("releases and the %d tags created by the sync", createdTags)
+	}
+
+	if viper.GetBool("DRY_RUN") {
+		pterm.Info.Printf("Dry run: %d %s would be deleted\n", len(candidates), action)
+		exitOnErrors(totalErrors)
+		return
+	}
+
+	if !confirm(fmt.Sprintf("Delete %d %s from %s?", len(candidates), action, viper.GetString("TARGET_ORGANIZATION"))) {
+		pterm.Info.Println("Rollback cancelled, nothing was deleted")
+		return
+	}
+
+	var deleted int
+	deleteSpinner, _ := pterm.DefaultSpinner.Start("Deleting releases...")
+	for _, c := range candidates {
+		deleteSpinner.UpdateText(fmt.Sprintf("Deleting release %s of %s", c.release.GetTagName(), c.repository))
+
+		err := api.DeleteTargetRelease(c.repository, c.release.GetID())
+		if err != nil {
+			totalErrors++
+			pterm.Warning.Printf("Error deleting release %s of %s: %v\n", c.release.GetTagName(), c.repository, err)
+			continue
+		}
+		deleted++
+
+		if viper.GetBool("DELETE_TAGS") && c.marker.CreatedTag {
+			err = api.DeleteTargetTag(c.repository, c.release.GetTagName())
+			if err != nil {
+				totalErrors++
+				pterm.Warning.Printf("Error deleting tag %s of %s: %v\n", c.release.GetTagName(), c.repository, err)
+			}
+		}
+	}
+	if totalErrors > 0 {
+		deleteSpinner.Fail("Some releases failed to delete")
+	} else {
+		deleteSpinner.Success("All releases deleted successfully!")
+	}
+
+	pterm.Info.Printf("Deleted: %d\n", deleted)
+	pterm.Info.Printf("Errors: %d\n", totalErrors)
+	exitOnErrors(totalErrors)
+}
+
+// confirm asks the user to confirm the deletion, unless --yes was given.
+// Without a terminal to prompt on, --yes is required.
+func confirm(question string) bool {
+	if viper.GetBool("YES") {
+		return true
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		pterm.Error.Println("Error: Not running in a terminal, use --yes to confirm the rollback or --dry-run to preview it")
+		os.Exit(1)
+	}
+
+	confirmed, err := pterm.DefaultInteractiveConfirm.Show(question)
+	if err != nil {
+		pterm.Error.Printf("Error reading confirmation: %v", err)
+		os.Exit(1)
+	}
+
+	return confirmed
+}
+
+func exitOnErrors(totalErrors int) {
+	if totalErrors > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	cleanupsMu sync.Mutex
+	cleanups   = make(map[int]func())
+	cleanupID  int
+)
+
+// addCleanup registers a function that must run before the sync exits, whether it finishes, fails or is
+// interrupted. The returned function runs it right away instead, at most once, and unregisters it.
+func addCleanup(cleanup func()) func() {
+	var once sync.Once
+	run := func() { once.Do(cleanup) }
+
+	cleanupsMu.Lock()
+	defer cleanupsMu.Unlock()
+	cleanupID++
+	id := cleanupID
+	cleanups[id] = run
+
+	return func() {
+		cleanupsMu.Lock()
+		delete(cleanups, id)
+		cleanupsMu.Unlock()
+		run()
+	}
+}
+
+// runCleanups runs every registered cleanup that hasn't run yet.
+func runCleanups() {
+	cleanupsMu.Lock()
+	pending := make([]func(), 0, len(cleanups))
+	for id, cleanup := range cleanups {
+		pending = append(pending, cleanup)
+		delete(cleanups, id)
+	}
+	cleanupsMu.Unlock()
+
+	for _, cleanup := range pending {
+		cleanup()
+	}
+}
+
+// exit runs the registered cleanups and exits, as deferred calls don't run on os.Exit.
+func exit(code int) {
+	runCleanups()
+	os.Exit(code)
+}
+
+// handleInterrupts runs the registered cleanups when the sync is interrupted or terminated, then exits.
+// The returned function stops handling signals.
+func handleInterrupts() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			slog.Warn("Sync interrupted, cleaning up before exiting", "signal", sig.String())
+			exit(1)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,185 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/actions"
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/spf13/viper"
+)
+
+// maxCommentLength is the longest comment body GitHub accepts.
+const maxCommentLength = 65536
+
+// issueStatus keeps a status comment on the triggering issue up to date while a sync runs.
+type issueStatus struct {
+	event        *actions.Event
+	commentID    int64
+	repositories []string
+}
+
+// newIssueStatus posts the status comment when the sync was triggered by an issue event, and returns nil
+// otherwise. The methods of a nil status do nothing, and errors are logged without failing the sync.
+func newIssueStatus(runID string, repositoryList []string) *issueStatus {
+	if !actions.Enabled() {
+		return nil
+	}
+	event, err := actions.LoadEvent()
+	if err != nil {
+		slog.Warn("Unable to read the triggering event, the issue status won't be updated", "error", err)
+		return nil
+	}
+	if event.IssueNumber == 0 {
+		return nil // skip if is not an issue event
+	}
+	if api.TargetType() != "github" && os.Getenv("GITHUB_TOKEN") == "" {
+		slog.Warn("GITHUB_TOKEN isn't set, the issue status won't be updated", "target_type", api.TargetType())
+		return nil
+	}
+
+	status := &issueStatus{event: event}
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+		status.repositories = append(status.repositories, owner+"/"+name)
+	}
+
+	commentID, err := api.CreateIssueComment(event.Owner, event.Repository, event.IssueNumber, status.render(&Report{RunID: runID}, false))
+	if err != nil {
+		slog.Error("Unable to create issue status comment", api.ErrorAttrs(err)...)
+		return nil
+	}
+	status.commentID = commentID
+
+	return status
+}
+
+// update shows the repositories synced so far and the one in progress.
+func (s *issueStatus) update(report *Report) {
+	if s == nil {
+		return
+	}
+	s.edit(s.render(report, false))
+}
+
+// finish shows the outcome of the sync and labels the issue with the labels configured for it.
+func (s *issueStatus) finish(report *Report) {
+	if s == nil {
+		return
+	}
+	s.edit(s.render(report, true))
+
+	labels := viper.GetString("ISSUE_SUCCESS_LABELS")
+	if report.Failed > 0 || report.FailedRepositories > 0 {
+		labels = viper.GetString("ISSUE_FAILURE_LABELS")
+	}
+	var names []string
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			names = append(names, label)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	err := api.AddIssueLabels(s.event.Owner, s.event.Repository, s.event.IssueNumber, names)
+	if err != nil {
+		slog.Error("Unable to label issue", append([]any{"labels", labels}, api.ErrorAttrs(err)...)...)
+	}
+}
+
+func (s *issueStatus) edit(body string) {
+	err := api.UpdateIssueComment(s.event.Owner, s.event.Repository, s.commentID, body)
+	if err != nil {
+		slog.Error("Unable to update issue status comment", api.ErrorAttrs(err)...)
+	}
+}
+
+// render renders the status comment, leaving out failure details and then the repository table
+// when the comment would be too long for GitHub.
+func (s *issueStatus) render(report *Report, finished bool) string {
+	var body string
+	for _, detail := range []int{2, 1, 0} {
+		body = s.renderDetail(report, finished, detail)
+		if len(body) <= maxCommentLength {
+			break
+		}
+	}
+	return body
+}
+
+func (s *issueStatus) renderDetail(report *Report, finished bool, detail int) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("## Release sync `%s`\n\n", report.RunID))
+
+	done := len(report.Repositories)
+	switch {
+	case !finished:
+		builder.WriteString("> [!NOTE]\n")
+		builder.WriteString(fmt.Sprintf("> ⏳ Syncing the releases of %d repositories: %d done so far.", len(s.repositories), done))
+	case report.Failed == 0 && report.FailedRepositories == 0:
+		builder.WriteString("> [!TIP]\n")
+		builder.WriteString(fmt.Sprintf("> ✅ Sync finished: all %d releases of %d repositories were synced.", report.Total, done))
+	default:
+		builder.WriteString("> [!CAUTION]\n")
+		builder.WriteString(fmt.Sprintf("> ❌ Sync finished with failures: %d of %d releases and %d of %d repositories failed to sync.",
+			report.Failed, report.Total, report.FailedRepositories, done))
+	}
+	if runURL := actions.RunURL(); runURL != "" {
+		builder.WriteString(fmt.Sprintf(" See the [workflow run](%s) for the logs.", runURL))
+	}
+	builder.WriteString("\n\n")
+
+	if detail == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString(repositoryTableHeader)
+	for _, result := range report.Repositories {
+		builder.WriteString(result.row())
+	}
+	for i, repository := range s.repositories {
+		if i < done || finished {
+			continue
+		}
+		status := "⏳ Pending"
+		if i == done {
+			status = "🔄 In progress"
+		}
+		builder.WriteString(fmt.Sprintf("| %s | | | | | | %s |\n", repository, status))
+	}
+
+	if detail == 1 {
+		return builder.String()
+	}
+
+	for _, result := range report.Repositories {
+		if result.Error == "" && len(result.Failures) == 0 {
+			continue
+		}
+
+		summary := fmt.Sprintf("%d of %d releases failed", result.Failed, result.Total)
+		if result.Error != "" {
+			summary = "repository could not be synced"
+		}
+		builder.WriteString(fmt.Sprintf("\n<details>\n<summary>❌ %s: %s</summary>\n\n", result.Repository, summary))
+		if result.Error != "" {
+			builder.WriteString("- " + markdownLine(result.Error) + "\n")
+		}
+		for _, failure := range result.Failures {
+			builder.WriteString(fmt.Sprintf("- `%s`: %s\n", failure.Release, markdownLine(failure.Reason)))
+		}
+		builder.WriteString("\n</details>\n")
+	}
+
+	return builder.String()
+}
+
+// markdownLine keeps a value on a single line of a markdown list.
+func markdownLine(value string) string {
+	return strings.NewReplacer("\r\n", "; ", "\n", "; ").Replace(value)
+}
@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/actions"
+	"github.com/spf13/viper"
+)
+
+// eventSettings maps the issue form fields and workflow_dispatch inputs that can be used
+// to the settings they provide. Hostnames are deliberately left out: the source credentials are
+// sent to the source hostname, which anyone able to open an issue must not be able to choose.
+var eventSettings = map[string]string{
+	"source-organization": "SOURCE_ORGANIZATION",
+	"target-organization": "TARGET_ORGANIZATION",
+	"repository":          "REPOSITORY",
+}
+
+// applyEventOptions fills the settings that weren't given as flags from the options of the triggering
+// event, so a sync can be driven entirely from an issue form or a workflow_dispatch run.
+// Repository lists and mapping files given inline are written to temporary files, which are returned.
+func applyEventOptions() ([]string, error) {
+	event, err := actions.LoadEvent()
+	if err != nil {
+		return nil, err
+	}
+	options := event.Options()
+
+	for key, setting := range eventSettings {
+		if options[key] != "" && viper.GetString(setting) == "" {
+			setEventOption(setting, key, options[key])
+		}
+	}
+
+	var tempFiles []string
+	repositoryList := firstOption(options, "repositories", "repository-list")
+	if repositoryList != "" && viper.GetString("REPOSITORY") == "" && viper.GetString("REPOSITORY_LIST") == "" && !viper.GetBool("ALL_REPOSITORIES") {
+		filePath, err := writeTempFile("repositories-*.txt", strings.Join(repositoryNames(repositoryList), "\n"))
+		if err != nil {
+			return tempFiles, err
+		}
+		tempFiles = append(tempFiles, filePath)
+		setEventOption("REPOSITORY_LIST", "repositories", filePath)
+	}
+
+	// The mapping is either a path in the workspace or the mapping itself
+	mappingFile := firstOption(options, "mapping", "mapping-file")
+	if mappingFile != "" && viper.GetString("MAPPING_FILE") == "" {
+		if strings.ContainsAny(mappingFile, ",\n") || strings.Contains(mappingFile, ": ") || strings.HasPrefix(strings.TrimSpace(mappingFile), "{") {
+			filePath, err := writeTempFile(inlineMappingPattern(mappingFile), mappingFile)
+			if err != nil {
+				return tempFiles, err
+			}
+			tempFiles = append(tempFiles, filePath)
+			mappingFile = filePath
+		}
+		setEventOption("MAPPING_FILE", "mapping", mappingFile)
+	}
+
+	return tempFiles, nil
+}
+
+// inlineMappingPattern returns the temporary file pattern of an inline mapping, with the extension
+// of its format so it is read as JSON, YAML or CSV.
+func inlineMappingPattern(mapping string) string {
+	mapping = strings.TrimSpace(mapping)
+	firstLine, _, _ := strings.Cut(mapping, "\n")
+
+	switch {
+	case strings.HasPrefix(mapping, "{") || strings.HasPrefix(mapping, "["):
+		return "mapping-*.json"
+	case strings.HasPrefix(mapping, "-") || (strings.Contains(firstLine, ":") && !strings.Contains(firstLine, ",")):
+		return "mapping-*.yaml"
+	default:
+		return "mapping-*.csv"
+	}
+}
+
+func setEventOption(setting string, option string, value string) {
+	slog.Debug("Using option from the triggering event", "option", option, "value", value)
+	os.Setenv("GHMT_"+setting, value)
+	viper.BindEnv(setting)
+}
+
+func firstOption(options map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if options[key] != "" {
+			return options[key]
+		}
+	}
+	return ""
+}
+
+// repositoryNames splits a repository list separated by lines, commas or spaces, ignoring list bullets.
+func repositoryNames(value string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if name != "-" && name != "*" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func writeTempFile(pattern string, content string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary file: %v", err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(strings.TrimSpace(content) + "\n")
+	if err != nil {
+		return "", fmt.Errorf("unable to write temporary file: %v", err)
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/actions"
+	"github.com/mona-actions/gh-migrate-releases/internal/notify"
+)
+
+// Result is the outcome of syncing the releases of one repository.
+type Result struct {
+	Repository string `json:"repository"`
+	Total      int    `json:"total"`
+	Created    int    `json:"created"`
+	Skipped    int    `json:"skipped"`
+	Failed     int    `json:"failed"`
+	// Assets counts the assets uploaded to the target
+	Assets   int       `json:"assets"`
+	Failures []Failure `json:"failures,omitempty"`
+	// Error is set when the repository could not be synced at all
+	Error string `json:"error,omitempty"`
+	// Unarchived is set when the archived target repository was unarchived for the sync, and
+	// Rearchived once it was archived again; RearchiveError is set when that failed
+	Unarchived     bool   `json:"unarchived,omitempty"`
+	Rearchived     bool   `json:"rearchived,omitempty"`
+	RearchiveError string `json:"rearchive_error,omitempty"`
+}
+
+// Failure is a release that failed to sync.
+type Failure struct {
+	Release string `json:"release"`
+	Reason  string `json:"reason"`
+	// Conflict is set when the release was skipped because its tag is used by a foreign release
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// Report is the outcome of a sync run.
+type Report struct {
+	RunID     string `json:"run_id"`
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// FailedRepositories counts repositories that could not be synced at all
+	FailedRepositories int       `json:"failed_repositories"`
+	Repositories       []*Result `json:"repositories"`
+}
+
+// fail records a release that failed to sync, and notifies it right away.
+func (r *Result) fail(release string, err error) {
+	r.addFailure(Failure{Release: release, Reason: err.Error()})
+}
+
+// conflict records a release skipped because its tag is used by a foreign target release.
+func (r *Result) conflict(release string, reason string) {
+	r.addFailure(Failure{Release: release, Reason: reason, Conflict: true})
+}
+
+func (r *Result) addFailure(failure Failure) {
+	r.Failed++
+	r.Failures = append(r.Failures, failure)
+
+	notifier.Send(notify.Event{
+		Type:       notify.ReleaseFailed,
+		Message:    fmt.Sprintf("❌ %s release %s failed to sync: %s", r.Repository, failure.Release, failure.Reason),
+		Repository: r.Repository,
+		Release:    failure.Release,
+		Error:      failure.Reason,
+	})
+}
+
+// summary describes the outcome of a repository in a sentence.
+func (r *Result) summary() string {
+	if r.Error != "" {
+		return fmt.Sprintf("❌ %s could not be synced: %s", r.Repository, r.Error)
+	}
+	icon := "✅"
+	if r.Failed > 0 {
+		icon = "⚠️"
+	}
+	return fmt.Sprintf("%s %s: %d of %d releases synced (%d created, %d skipped, %d failed, %d assets uploaded)",
+		icon, r.Repository, r.Total-r.Failed, r.Total, r.Created, r.Skipped, r.Failed, r.Assets)
+}
+
+// add records the result of a repository in the report totals.
+func (r *Report) add(result *Result) {
+	r.Repositories = append(r.Repositories, result)
+	r.Total += result.Total
+	r.Failed += result.Failed
+	r.Succeeded = r.Total - r.Failed
+	if result.Error != "" {
+		r.FailedRepositories++
+	}
+}
+
+// status describes the outcome of a repository for the summary table.
+func (r *Result) status() string {
+	switch {
+	case r.Error != "":
+		return "❌ Error"
+	case r.Failed > 0:
+		return "⚠️ Partial"
+	default:
+		return "✅ Synced"
+	}
+}
+
+const repositoryTableHeader = "| Repository | Releases | Created | Skipped | Failed | Assets | Status |\n" +
+	"| ---------- | -------- | ------- | ------- | ------ | ------ | ------ |\n"
+
+// row renders the result as a row of the repository table.
+func (r *Result) row() string {
+	return fmt.Sprintf("| %s | %d | %d | %d | %d | %d | %s |\n",
+		r.Repository, r.Total, r.Created, r.Skipped, r.Failed, r.Assets, r.status())
+}
+
+// Markdown renders the report as a job summary.
+func (r *Report) Markdown() string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("## Release sync `%s`\n\n", r.RunID))
+	builder.WriteString("| No. of Releases | Succeeded | Failed |\n")
+	builder.WriteString("| --------------- | --------- | ------ |\n")
+	builder.WriteString(fmt.Sprintf("| %d | %d | %d |\n\n", r.Total, r.Succeeded, r.Failed))
+
+	builder.WriteString(repositoryTableHeader)
+	for _, result := range r.Repositories {
+		builder.WriteString(result.row())
+	}
+
+	var archived []string
+	for _, result := range r.Repositories {
+		switch {
+		case result.RearchiveError != "":
+			archived = append(archived, fmt.Sprintf("| %s | ❌ Left unarchived: %s |", result.Repository, markdownCell(result.RearchiveError)))
+		case result.Unarchived:
+			archived = append(archived, fmt.Sprintf("| %s | ✅ Re-archived |", result.Repository))
+		}
+	}
+	if len(archived) > 0 {
+		builder.WriteString("\n### Temporarily Unarchived Targets\n\n")
+		builder.WriteString("| Repository | Status |\n")
+		builder.WriteString("| ---------- | ------ |\n")
+		builder.WriteString(strings.Join(archived, "\n") + "\n")
+	}
+
+	var failures []string
+	for _, result := range r.Repositories {
+		if result.Error != "" {
+			failures = append(failures, fmt.Sprintf("| %s | | %s |", result.Repository, markdownCell(result.Error)))
+		}
+		for _, failure := range result.Failures {
+			failures = append(failures, fmt.Sprintf("| %s | %s | %s |", result.Repository, failure.Release, markdownCell(failure.Reason)))
+		}
+	}
+	if len(failures) > 0 {
+		builder.WriteString("\n### Failures\n\n")
+		builder.WriteString("| Repository | Release | Reason |\n")
+		builder.WriteString("| ---------- | ------- | ------ |\n")
+		builder.WriteString(strings.Join(failures, "\n") + "\n")
+	}
+
+	return builder.String()
+}
+
+// annotate emits a workflow annotation for every failed repository and release.
+func (r *Report) annotate() {
+	for _, result := range r.Repositories {
+		if result.Error != "" {
+			actions.Error("Release sync failed: "+result.Repository, result.Error)
+		}
+		if result.RearchiveError != "" {
+			actions.Warning("Target repository left unarchived: "+result.Repository, "Unable to re-archive the target repository, it must be archived manually: "+result.RearchiveError)
+		}
+		for _, failure := range result.Failures {
+			title := fmt.Sprintf("Release sync failed: %s %s", result.Repository, failure.Release)
+			if failure.Conflict {
+				actions.Warning(title, failure.Reason)
+			} else {
+				actions.Error(title, failure.Reason)
+			}
+		}
+	}
+}
+
+// markdownCell makes a value safe to use in a markdown table cell.
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ").Replace(value)
+}
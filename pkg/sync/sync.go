@@ -1,177 +1,558 @@
 package sync
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"strings"
+	"strconv"
 
+	"github.com/google/go-github/v62/github"
+	"github.com/mona-actions/gh-migrate-releases/internal/actions"
 	"github.com/mona-actions/gh-migrate-releases/internal/api"
 	"github.com/mona-actions/gh-migrate-releases/internal/files"
 	"github.com/mona-actions/gh-migrate-releases/internal/mapping"
+	"github.com/mona-actions/gh-migrate-releases/internal/notify"
+	"github.com/mona-actions/gh-migrate-releases/internal/progress"
+	"github.com/mona-actions/gh-migrate-releases/internal/releases"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/mona-actions/gh-migrate-releases/pkg/preflight"
+	"github.com/mona-actions/gh-migrate-releases/pkg/validate"
 	"github.com/pterm/pterm"
 	"github.com/spf13/viper"
 )
 
+var (
+	// target is the platform releases are synced to.
+	target api.Target
+	// notifier posts the events of the sync to the notification webhook, when one is configured.
+	notifier *notify.Notifier
+)
+
 func SyncReleases() {
+	// Temporary files and unarchived targets are cleaned up even when the sync is interrupted
+	defer handleInterrupts()()
+
+	// Read the options that weren't given as flags from the triggering issue or workflow_dispatch event
+	if viper.GetBool("ISSUE_OPS") {
+		tempFiles, err := applyEventOptions()
+		for _, tempFile := range tempFiles {
+			tempFile := tempFile
+			defer addCleanup(func() { os.Remove(tempFile) })()
+		}
+		if err != nil {
+			slog.Error("Unable to read options from the triggering event", "error", err)
+			exit(1)
+		}
+	}
+
 	// Get all releases from source repository
 	checkVars()
 
-	var totalReleases, totalFailed int
+	// Connect to the platform releases are synced to
+	var err error
+	target, err = api.NewTarget()
+	if err != nil {
+		slog.Error("Invalid target", "error", err)
+		exit(1)
+	}
+
+	// Load handle mapping once for all repositories
+	var handles mapping.HandleMap
+	if viper.GetString("MAPPING_FILE") != "" {
+		var err error
+		handles, err = mapping.LoadHandleMap(viper.GetString("MAPPING_FILE"))
+		if err != nil {
+			slog.Error("Unable to read mapping file", "file", viper.GetString("MAPPING_FILE"), "error", err)
+			exit(1)
+		}
+	}
 
-	if viper.GetString("REPOSITORY_LIST") != "" {
-		// Read repository list from file
-		repositories, err := files.ReadRepositoryListFromFile(viper.GetString("REPOSITORY_LIST"))
+	// Load issue/PR number mapping once for all repositories
+	var numbers mapping.NumberMap
+	if viper.GetString("NUMBER_MAPPING_FILE") != "" {
+		var err error
+		numbers, err = mapping.LoadNumberMap(viper.GetString("NUMBER_MAPPING_FILE"))
 		if err != nil {
-			pterm.Error.Printf("Error reading repository list: %v", err)
-			os.Exit(1)
+			slog.Error("Unable to read number mapping file", "file", viper.GetString("NUMBER_MAPPING_FILE"), "error", err)
+			exit(1)
 		}
+	}
 
-		// Loop through each repository in the list
-		for _, repository := range repositories {
+	// Load the tokens of users that releases should be created as
+	var authorTokens mapping.TokenMap
+	if viper.GetString("AUTHOR_TOKEN_FILE") != "" {
+		var err error
+		authorTokens, err = mapping.LoadTokenMap(viper.GetString("AUTHOR_TOKEN_FILE"))
+		if err != nil {
+			slog.Error("Unable to read author token file", "file", viper.GetString("AUTHOR_TOKEN_FILE"), "error", err)
+			exit(1)
+		}
+	}
 
-			releasesCount, failedReleases, err := migrateRepositoryReleases(repository)
+	// Parse the source details footer once for all releases
+	var footer *mapping.Footer
+	if !viper.GetBool("NO_FOOTER") {
+		footerTemplate := mapping.DefaultFooterTemplate
+		if viper.GetString("FOOTER_TEMPLATE") != "" {
+			content, err := os.ReadFile(viper.GetString("FOOTER_TEMPLATE"))
 			if err != nil {
-				pterm.Error.Printf("Error migrating repository releases: %v", err)
+				slog.Error("Unable to read footer template", "file", viper.GetString("FOOTER_TEMPLATE"), "error", err)
+				exit(1)
 			}
+			footerTemplate = string(content)
+		}
 
-			totalReleases += releasesCount
-			totalFailed += failedReleases
+		var err error
+		footer, err = mapping.NewFooter(footerTemplate, viper.GetString("TIME_ZONE"), handles)
+		if err != nil {
+			slog.Error("Invalid footer template", "error", err)
+			exit(1)
+		}
+	}
+
+	repositoryList, err := repositories.List()
+	if err != nil {
+		slog.Error("Unable to list repositories", "error", err)
+		exit(1)
+	}
 
+	// Unqualified number mappings can't tell which repository they are meant for
+	if numbers.Unqualified() && len(repositoryList) > 1 {
+		slog.Error("Number mapping rows must include the repository (repository,source,target) when syncing more than one repository", "file", viper.GetString("NUMBER_MAPPING_FILE"))
+		exit(1)
+	}
+
+	// Check credentials and repository access before creating anything
+	if !viper.GetBool("SKIP_PREFLIGHT") && !preflight.RunChecks(repositoryList) {
+		slog.Error("Pre-flight checks failed, no releases were created")
+		exit(1)
+	}
+
+	// Check that mapped handles exist in the target before creating anything
+	// Handles are checked against GitHub users, so only on GitHub targets
+	if handles != nil && !viper.GetBool("SKIP_MAPPING_VALIDATION") && api.TargetType() == "github" {
+		validateMappingSpinner, _ := pterm.DefaultSpinner.Start("Validating mapped handles in target...")
+		problems, err := validate.CheckTargetHandles(handles)
+		if err != nil {
+			validateMappingSpinner.Fail()
+			slog.Error("Unable to validate mapping file", api.ErrorAttrs(err)...)
+			exit(1)
 		}
-	} else if viper.GetString("REPOSITORY") != "" {
-		// Migrate releases from a single repository
-		repository := viper.GetString("REPOSITORY")
+		if len(problems) > 0 {
+			validateMappingSpinner.Fail("Mapping file contains invalid target handles")
+			validate.PrintProblems(problems)
+			exit(1)
+		}
+		validateMappingSpinner.Success("Mapped handles validated successfully!")
+	}
+
+	// Every release created by this run is marked with the run id so it can be rolled back
+	runID := releases.NewRunID()
+	slog.Info("Sync started", "run_id", runID, "repositories", len(repositoryList))
 
-		releasesCount, failedReleases, err := migrateRepositoryReleases(repository)
+	// Post the progress of the sync to the notification webhook, if any
+	if viper.GetString("NOTIFY_WEBHOOK") != "" {
+		notifier, err = newNotifier(runID)
 		if err != nil {
-			pterm.Error.Printf("Error migrating repository releases: %v", err)
+			slog.Error("Invalid notification webhook", "error", err)
+			exit(1)
 		}
+	}
+	notifier.Send(notify.Event{
+		Type:    notify.RunStarted,
+		Message: fmt.Sprintf("Release sync %s started for %d repositories", runID, len(repositoryList)),
+		Data:    map[string]int{"repositories": len(repositoryList)},
+	})
 
-		totalReleases += releasesCount
-		totalFailed += failedReleases
+	// Keep the triggering issue, if any, posted on the progress of the sync
+	status := newIssueStatus(runID, repositoryList)
 
-	} else {
-		pterm.Error.Println("Error: No repository or repository list specified")
-		os.Exit(1)
+	// Loop through each repository in the list
+	report := &Report{RunID: runID}
+	for i, repository := range repositoryList {
+		progress.SetRepository(i+1, len(repositoryList), repository)
+		if i > 0 {
+			status.update(report)
+		}
+
+		result, err := migrateRepositoryReleases(repository, handles, numbers, footer, authorTokens, runID)
+		if err != nil {
+			slog.Error("Unable to migrate repository releases", append([]any{"repository", result.Repository}, api.ErrorAttrs(err)...)...)
+			result.Error = err.Error()
+		}
+
+		report.add(result)
+		notifier.Send(notify.Event{
+			Type:       notify.RepositoryCompleted,
+			Message:    result.summary(),
+			Repository: result.Repository,
+			Error:      result.Error,
+			Data:       result,
+		})
 	}
 
-	// checks if running in a GitHub Actions Environment
-	if os.Getenv("CI") == "true" && os.Getenv("GITHUB_ACTIONS") == "true" {
-		// Print in a README Table format the number of releases created
-		message := fmt.Sprintf(
-			"| No. of Releases | Succeeded | Failed |\n"+
-				"| --------------- | --------- | ------ |\n"+
-				"| %d | %d | %d |\n",
-			totalReleases, totalReleases-totalFailed, totalFailed,
-		)
-		organization, repository, issueNumber, err := api.GetDatafromGitHubContext()
-		if issueNumber == 0 {
-			return // skip if is not an issue event
+	slog.Info("Sync finished", "run_id", runID, "total", report.Total, "succeeded", report.Succeeded, "failed", report.Failed)
+	notifier.Send(notify.Event{
+		Type: notify.RunFinished,
+		Message: fmt.Sprintf("Release sync %s finished: %d of %d releases synced, %d failed, %d of %d repositories could not be synced",
+			runID, report.Succeeded, report.Total, report.Failed, report.FailedRepositories, len(report.Repositories)),
+		Data: report,
+	})
+
+	// Write the detailed report for later steps and troubleshooting
+	reportFile := viper.GetString("REPORT_FILE")
+	if reportFile == "" && actions.Enabled() {
+		reportFile = "gh-migrate-releases-" + runID + ".json"
+	}
+	if reportFile != "" {
+		err := files.CreateJSON(report, reportFile)
+		if err != nil {
+			slog.Error("Unable to write sync report", "file", reportFile, "error", err)
 		} else {
-			if err != nil {
-				pterm.Error.Printf("Error getting issue number: %v", err)
-			}
-			err = api.WriteToIssue(organization, repository, issueNumber, message)
-			if err != nil {
-				pterm.Error.Printf("Error writing releases table to issue: %v", err)
-			}
+			slog.Info("Sync report written", "file", reportFile)
+		}
+	}
+
+	// checks if running in a GitHub Actions Environment
+	if actions.Enabled() {
+		report.annotate()
+
+		err := actions.AppendSummary(report.Markdown())
+		if err != nil {
+			slog.Error("Unable to write job summary", "error", err)
 		}
-	} else {
-		pterm.Info.Printf("Total Releases: %d\n", totalReleases)
-		pterm.Info.Printf("Succeeded: %d\n", totalReleases-totalFailed)
-		pterm.Info.Printf("Failed: %d\n", totalFailed)
 
+		err = actions.SetOutputs(map[string]string{
+			"total":               strconv.Itoa(report.Total),
+			"succeeded":           strconv.Itoa(report.Succeeded),
+			"failed":              strconv.Itoa(report.Failed),
+			"failed-repositories": strconv.Itoa(report.FailedRepositories),
+			"report-path":         reportFile,
+		})
+		if err != nil {
+			slog.Error("Unable to set step outputs", "error", err)
+		}
+
+		status.finish(report)
 	}
 
 }
 
 func checkVars() {
 	//check that repository and repository list are not sent at the same time
-	if viper.GetString("REPOSITORY") != "" && viper.GetString("REPOSITORY_LIST") != "" {
-		pterm.Error.Println("Error: Cannot specify both a repository and a repository list")
-		os.Exit(1)
+	if viper.GetString("TARGET_ORGANIZATION") == "" {
+		slog.Error("Target organization is required")
+		exit(1)
+	} else if viper.GetString("REPOSITORY") != "" && viper.GetString("REPOSITORY_LIST") != "" {
+		slog.Error("Cannot specify both a repository and a repository list")
+		exit(1)
 	} else if viper.GetString("REPOSITORY") != "" && viper.GetString("SOURCE_ORGANIZATION") == "" {
-		pterm.Error.Println("Error: Source organization is required when specifying a repository")
-		os.Exit(1)
+		slog.Error("Source organization is required when specifying a repository")
+		exit(1)
+	} else if viper.GetBool("ALL_REPOSITORIES") && viper.GetString("SOURCE_ORGANIZATION") == "" {
+		slog.Error("Source organization is required when syncing all repositories")
+		exit(1)
+	}
+
+	// GitHub specific options can't be used with other target platforms
+	if api.TargetType() != "github" {
+		for setting, option := range map[string]string{
+			"UNARCHIVE_TEMPORARILY": "--unarchive-temporarily",
+			"AUTHOR_TOKEN_FILE":     "--author-token-file",
+			"DISCUSSION_CATEGORY":   "--discussion-category",
+		} {
+			if viper.GetString(setting) != "" && viper.GetString(setting) != "false" {
+				slog.Error("Option is only supported with GitHub targets", "option", option, "target_type", api.TargetType())
+				exit(1)
+			}
+		}
+	}
+
+	// check that the notification webhook is valid before the sync starts
+	if viper.GetString("NOTIFY_WEBHOOK") != "" {
+		if _, err := newNotifier(""); err != nil {
+			slog.Error("Invalid notification webhook", "error", err)
+			exit(1)
+		}
+	}
+
+	// check that transfer rates are valid before any transfer starts
+	for _, setting := range []string{"MAX_DOWNLOAD_RATE", "MAX_UPLOAD_RATE"} {
+		if _, err := api.ParseRate(viper.GetString(setting)); err != nil {
+			slog.Error("Invalid transfer rate", "error", err)
+			exit(1)
+		}
 	}
 }
 
-func migrateRepositoryReleases(repository string) (int, int, error) {
-	var owner string
-	// if repository includes owner, split it
-	if strings.Contains(repository, "/") {
-		repositoryParts := strings.Split(repository, "/")
-		owner = repositoryParts[0]
-		repository = repositoryParts[1]
-	} else {
-		owner = viper.GetString("SOURCE_ORGANIZATION")
+// newNotifier creates the notifier of the notification webhook, signing with the secret from the
+// notify secret option or the NOTIFY_WEBHOOK_SECRET environment variable.
+func newNotifier(runID string) (*notify.Notifier, error) {
+	secret := viper.GetString("NOTIFY_SECRET")
+	if secret == "" {
+		secret = os.Getenv("NOTIFY_WEBHOOK_SECRET")
 	}
 
-	fetchReleasesSpinner, _ := pterm.DefaultSpinner.Start("Fetching releases from repository: ", repository)
-	releases, err := api.GetSourceRepositoryReleases(owner, repository)
+	return notify.New(viper.GetString("NOTIFY_WEBHOOK"), secret, viper.GetString("NOTIFY_FORMAT"), runID, actions.RunURL())
+}
+
+func migrateRepositoryReleases(repository string, handles mapping.HandleMap, numbers mapping.NumberMap, footer *mapping.Footer, authorTokens mapping.TokenMap, runID string) (*Result, error) {
+	owner, repository := repositories.Split(repository)
+	logger := slog.With("repository", owner+"/"+repository, "run_id", runID)
+	result := &Result{Repository: owner + "/" + repository}
+
+	links := mapping.NewLinkRewriter(owner, repository, numbers)
+	sourceHost := links.SourceHost
+
+	// GitLab references merge requests as !N, so short references need to know which numbers are pull requests
+	if links.GitLab {
+		pullRequests, err := api.GetSourcePullRequestNumbers(owner, repository)
+		if err != nil {
+			logger.Warn("Unable to list source pull requests, their short references will be kept as issue references", api.ErrorAttrs(err)...)
+		}
+		links.PullRequests = pullRequests
+	}
+
+	// Archived targets are read-only, unarchive them for the duration of the sync
+	if viper.GetBool("UNARCHIVE_TEMPORARILY") {
+		unarchived, err := unarchiveTarget(repository, logger)
+		if err != nil {
+			return result, err
+		}
+		if unarchived {
+			result.Unarchived = true
+			// Re-archive when the repository is done, or before exiting if the sync fails or is interrupted
+			defer addCleanup(func() { rearchiveTarget(repository, result, logger) })()
+		}
+	}
+
+	fetchReleasesSpinner, _ := pterm.DefaultSpinner.Start(progress.Prefix()+"Fetching releases from repository: ", repository)
+	sourceReleases, sourceDiscussions, err := api.GetSourceRepositoryReleasesWithDiscussions(owner, repository)
 	if err != nil {
-		pterm.Fatal.Printf("Error: %v", err)
 		fetchReleasesSpinner.Fail()
+		return result, err
+	}
+	fetchReleasesSpinner.Success(fmt.Sprintf("%s%d Releases fetched successfully!", progress.Prefix(), len(sourceReleases)))
+
+	// List target releases up front to tell releases synced by a prior run from foreign ones
+	targetReleases, err := target.ListReleases(repository)
+	if err != nil {
+		return result, err
+	}
+	index := releases.NewIndex(targetReleases, sourceHost, owner+"/"+repository)
+
+	// The latest release and discussion categories are carried over explicitly
+	sourceLatest, err := api.GetSourceLatestReleaseTag(owner, repository)
+	if err != nil {
+		return result, err
+	}
+	targetCategories, sourceCategories, err := discussionCategories(owner, repository, sourceDiscussions)
+	if err != nil {
+		logger.Warn("Unable to get discussion categories, releases will be created without discussions", api.ErrorAttrs(err)...)
 	}
-	fetchReleasesSpinner.UpdateText(fmt.Sprintf(" %d Releases fetched successfully!", len(releases)))
-	fetchReleasesSpinner.Success()
 
 	// Create releases in target repository
-	createReleasesSpinner, _ := pterm.DefaultSpinner.Start("Creating releases in target repository...", repository)
-	var failed int
-	releasesCount := len(releases)
+	releasesCount := len(sourceReleases)
+	result.Total = releasesCount
 	//loop through each release and create it in the target repository
-	for _, release := range releases {
-		createReleasesSpinner.UpdateText("Creating release: " + release.GetName())
+	for i, release := range sourceReleases {
+		progress.SetRelease(i+1, releasesCount, release.GetTagName())
+		releaseLogger := logger.With("release", release.GetTagName())
 
-		// Modify release body to map new handles and map old urls to new urls
-		release, err := mapping.AddSourceTimeStamps(release)
-		if err != nil {
-			pterm.Warning.Printf("Error adding source timestamps: %v", err)
-		}
-		release.Body, err = mapping.ModifyReleaseBody(release.Body, viper.GetString("MAPPING_FILE"))
-		if err != nil {
-			pterm.Warning.Printf("Error modifying release body: %v", err)
+		// Upload the archives generated by the source host, as the target regenerates them with other checksums
+		if viper.GetBool("PRESERVE_SOURCE_ARCHIVES") {
+			archives, err := api.SourceArchiveAssets(repository, release)
+			if err != nil {
+				releaseLogger.Warn("Unable to preserve source archives", "error", err)
+			}
+			// Assets already published under the archive names take precedence
+			release.Assets = append(release.Assets, releases.MissingAssets(&github.RepositoryRelease{Assets: archives}, release)...)
 		}
-		// Create release api call
-		newRepository := repository
-		newRelease, err := api.CreateRelease(newRepository, release)
-		if err != nil {
-			if strings.Contains(err.Error(), "already exists") {
-				pterm.Info.Printf("Release already exists: %v... skipping", release.GetName())
+
+		status, targetRelease := index.Classify(release)
+		switch status {
+		case releases.Synced:
+			// Resume releases whose assets didn't all make it in the prior run
+			missingAssets := releases.MissingAssets(release, targetRelease)
+			if len(missingAssets) == 0 {
+				releaseLogger.Info("Release already synced, skipping")
+				result.Skipped++
 				continue
+			}
+			releaseLogger.Info("Release already synced, uploading missing assets", "assets", len(missingAssets))
+			uploaded, err := migrateAssets(repository, missingAssets, targetRelease, releaseLogger)
+			result.Assets += uploaded
+			if err != nil {
+				result.fail(release.GetTagName(), err)
 			} else {
-				failed++
-				createReleasesSpinner.Fail()
-				pterm.Warning.Printf("Error creating release: %v", err)
+				result.Created++
 			}
+			continue
+		case releases.Conflict:
+			releaseLogger.Warn("Tag is already used by a target release that was not synced from this source release, skipping", "target_release", targetRelease.GetName())
+			result.conflict(release.GetTagName(), fmt.Sprintf("tag is already used by target release %q, which was not synced from this source release", targetRelease.GetName()))
+			continue
 		}
-		// Download assets from source repository and upload to target repository
-		for _, asset := range release.Assets {
 
-			err := api.DownloadReleaseAssets(asset)
-			createReleasesSpinner.UpdateText("Downloading asset..." + asset.GetName())
+		createReleaseSpinner, _ := pterm.DefaultSpinner.Start(progress.Prefix() + "Creating release: " + release.GetName())
+
+		// Modify release body to map new handles and map old urls to new urls
+		release.Body = mapping.ModifyReleaseBody(release.Body, handles, links)
+
+		// Add source release details after mapping so the footer keeps the original urls
+		if footer != nil {
+			release, err = footer.Apply(release)
 			if err != nil {
-				pterm.Error.Printf("Error downloading assets: %v", err)
+				releaseLogger.Warn("Unable to add release footer", "error", err)
 			}
-			createReleasesSpinner.UpdateText("Uploading assets..." + asset.GetName())
+		}
 
-			err = api.UploadAssetViaURL(newRelease.GetUploadURL(), asset)
+		// Tags that came over with the git history must survive a rollback, only those created with the release are recorded
+		var createdTag bool
+		if api.TargetType() == "github" {
+			tagExists, err := api.TargetTagExists(repository, release.GetTagName())
 			if err != nil {
-				pterm.Error.Printf("Error uploading assets: %v", err)
-				createReleasesSpinner.Fail()
+				releaseLogger.Warn("Unable to check whether the tag exists in the target, a rollback will keep it", api.ErrorAttrs(err)...)
 			}
+			createdTag = err == nil && !tagExists
+		}
+
+		// Mark the release with its source and this run, for rollback and later runs
+		release.Body = github.String(releases.AddMarker(release.GetBody(), releases.Marker{
+			SourceHost:       sourceHost,
+			SourceRepository: owner + "/" + repository,
+			SourceReleaseID:  release.GetID(),
+			RunID:            runID,
+			CreatedTag:       createdTag,
+		}))
+
+		// Link a new discussion when the source release had one and the target has a matching category
+		var discussionCategory string
+		if sourceCategory, ok := sourceCategories[release.GetID()]; ok {
+			discussionCategory = releases.MatchDiscussionCategory(sourceCategory, targetCategories, viper.GetString("DISCUSSION_CATEGORY"))
+			if discussionCategory == "" {
+				releaseLogger.Warn("Discussion category doesn't exist in the target repository, no discussion will be created", "category", sourceCategory)
+			}
+		}
+		request := releases.NewCreateRequest(release, release.GetBody(), release.GetTagName() == sourceLatest, discussionCategory)
+
+		// Create release api call, as the mapped author when they supplied a token
+		newRepository := repository
+		author := handles.Lookup(release.GetAuthor().GetLogin())
+		var newRelease *github.RepositoryRelease
+		if token, ok := authorTokens.Lookup(author); ok {
+			newRelease, err = api.CreateReleaseAs(newRepository, request, token)
+			if err != nil {
+				releaseLogger.Warn("Unable to create release as its author, falling back to target token", append([]any{"author", author}, api.ErrorAttrs(err)...)...)
+				newRelease, err = target.CreateRelease(newRepository, request)
+			}
+		} else {
+			newRelease, err = target.CreateRelease(newRepository, request)
+		}
+		if err != nil {
+			createReleaseSpinner.Fail(progress.Prefix() + "Unable to create release: " + release.GetName())
+			releaseLogger.Error("Unable to create release", api.ErrorAttrs(err)...)
+			result.fail(release.GetTagName(), err)
+			continue
+		}
+		index.Add(newRelease)
+		createReleaseSpinner.Success(progress.Prefix() + "Release created: " + release.GetName())
+
+		// Download assets from source repository and upload to target repository
+		uploaded, err := migrateAssets(repository, release.Assets, newRelease, releaseLogger)
+		result.Assets += uploaded
+		if err != nil {
+			result.fail(release.GetTagName(), err)
+		} else {
+			result.Created++
 		}
 	}
 
-	if failed > 0 {
-		createReleasesSpinner.UpdateText("Some Releases failed to create")
-		createReleasesSpinner.Fail()
-		return releasesCount, failed, fmt.Errorf("some releases failed to create")
+	if result.Failed > 0 {
+		logger.Error("Some releases failed to sync", "failed", result.Failed, "total", releasesCount)
 	} else {
-		createReleasesSpinner.UpdateText("All Releases created successfully!")
-		createReleasesSpinner.Success()
-		return releasesCount, failed, nil
+		logger.Info("All releases synced successfully", "total", releasesCount)
+	}
+
+	return result, nil
+
+}
+
+// discussionCategories returns the discussion categories of the target repository and the categories of the
+// source release discussions by release id. Both are empty when the target repository has discussions disabled,
+// when no source release has a discussion, or on error.
+func discussionCategories(owner string, repository string, sourceDiscussions map[int64]int) ([]string, map[int64]string, error) {
+	if api.TargetType() != "github" || len(sourceDiscussions) == 0 {
+		return nil, nil, nil
+	}
+
+	targetCategories, err := api.GetTargetDiscussionCategories(repository)
+	if err != nil || targetCategories == nil {
+		return nil, nil, err
+	}
+
+	sourceCategories, err := api.GetSourceReleaseDiscussionCategories(owner, repository, sourceDiscussions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return targetCategories, sourceCategories, nil
+}
+
+// migrateAssets downloads assets from the source repository and uploads them to a target release.
+// Every asset is attempted; the number uploaded is returned with the errors of those that failed.
+func migrateAssets(repository string, assets []*github.ReleaseAsset, targetRelease *github.RepositoryRelease, logger *slog.Logger) (int, error) {
+	var uploaded int
+	var errs []error
+	for _, asset := range assets {
+		err := api.DownloadReleaseAssets(asset)
+		if err != nil {
+			logger.Error("Unable to download asset", append([]any{"asset", asset.GetName()}, api.ErrorAttrs(err)...)...)
+			errs = append(errs, fmt.Errorf("unable to download asset %s: %w", asset.GetName(), err))
+			continue
+		}
+
+		err = target.UploadAsset(repository, targetRelease, asset)
+		if err != nil {
+			logger.Error("Unable to upload asset", append([]any{"asset", asset.GetName()}, api.ErrorAttrs(err)...)...)
+			errs = append(errs, fmt.Errorf("unable to upload asset %s: %w", asset.GetName(), err))
+			continue
+		}
+		uploaded++
 	}
 
+	return uploaded, errors.Join(errs...)
+}
+
+// unarchiveTarget unarchives the target repository if it is archived, and returns whether it did.
+func unarchiveTarget(repository string, logger *slog.Logger) (bool, error) {
+	targetRepository, err := api.GetTargetRepository(repository)
+	if err != nil {
+		return false, fmt.Errorf("unable to get target repository: %v", err)
+	}
+	if targetRepository == nil || !targetRepository.GetArchived() {
+		return false, nil
+	}
+
+	err = api.SetTargetRepositoryArchived(repository, false)
+	if err != nil {
+		return false, fmt.Errorf("unable to unarchive target repository: %v", err)
+	}
+	logger.Info("Unarchived target repository for the sync", "target_repository", viper.GetString("TARGET_ORGANIZATION")+"/"+repository)
+
+	return true, nil
+}
+
+// rearchiveTarget archives a target repository that was unarchived for the sync, and records the outcome in its result.
+func rearchiveTarget(repository string, result *Result, logger *slog.Logger) {
+	err := api.SetTargetRepositoryArchived(repository, true)
+	if err != nil {
+		logger.Error("Unable to re-archive target repository, it must be archived manually",
+			append([]any{"target_repository", viper.GetString("TARGET_ORGANIZATION") + "/" + repository}, api.ErrorAttrs(err)...)...)
+		result.RearchiveError = err.Error()
+		return
+	}
+	result.Rearchived = true
+	logger.Info("Re-archived target repository", "target_repository", viper.GetString("TARGET_ORGANIZATION")+"/"+repository)
 }
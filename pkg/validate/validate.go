@@ -0,0 +1,147 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/mapping"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// Problem describes a handle that can't be safely used in migrated release notes.
+type Problem struct {
+	Handle string
+	Reason string
+}
+
+func ValidateMapping() {
+	handles, err := mapping.LoadHandleMap(viper.GetString("MAPPING_FILE"))
+	if err != nil {
+		pterm.Error.Printf("Error reading mapping file: %v", err)
+		os.Exit(1)
+	}
+
+	validateMappingSpinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Validating %d mapped handles in target...", len(handles)))
+	problems, err := CheckTargetHandles(handles)
+	if err != nil {
+		validateMappingSpinner.Fail()
+		pterm.Error.Printf("Error validating mapping file: %v", err)
+		os.Exit(1)
+	}
+	validateMappingSpinner.Success("Mapped handles checked!")
+
+	if viper.GetBool("CHECK_MENTIONS") {
+		repositoryList, err := repositories.List()
+		if err != nil {
+			pterm.Error.Printf("Error: %v", err)
+			os.Exit(1)
+		}
+
+		mentionsSpinner, _ := pterm.DefaultSpinner.Start("Checking release mentions in source repositories...")
+		unmapped, err := FindUnmappedMentions(repositoryList, handles)
+		if err != nil {
+			mentionsSpinner.Fail()
+			pterm.Error.Printf("Error checking release mentions: %v", err)
+			os.Exit(1)
+		}
+		mentionsSpinner.Success("Release mentions checked!")
+
+		problems = append(problems, unmapped...)
+	}
+
+	if len(problems) > 0 {
+		PrintProblems(problems)
+		os.Exit(1)
+	}
+
+	pterm.Success.Println("Mapping file is valid")
+}
+
+// CheckTargetHandles resolves every target handle of the mapping against the target instance
+// and returns the ones that don't exist or are suspended.
+func CheckTargetHandles(handles mapping.HandleMap) ([]Problem, error) {
+	// Several source handles may map to the same target handle, only look each one up once
+	targets := make(map[string]bool)
+	for _, target := range handles {
+		targets[target] = true
+	}
+
+	var problems []Problem
+	for _, target := range sortedKeys(targets) {
+		user, err := api.GetTargetUser(target)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get user %s: %v", target, err)
+		}
+
+		if user == nil {
+			problems = append(problems, Problem{Handle: target, Reason: "target user not found"})
+		} else if user.SuspendedAt != nil {
+			problems = append(problems, Problem{Handle: target, Reason: "target user is suspended"})
+		}
+	}
+
+	return problems, nil
+}
+
+// FindUnmappedMentions returns the handles @mentioned in the releases of the source repositories
+// that have no entry in the mapping.
+func FindUnmappedMentions(repositoryList []string, handles mapping.HandleMap) ([]Problem, error) {
+	mapped := make(map[string]bool)
+	for source := range handles {
+		mapped[strings.ToLower(source)] = true
+	}
+
+	// Handle -> repositories it is mentioned in
+	unmapped := make(map[string][]string)
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+		releases, err := api.GetSourceRepositoryReleases(owner, name)
+		if err != nil {
+			return nil, err
+		}
+
+		mentioned := make(map[string]bool)
+		for _, release := range releases {
+			for _, handle := range mapping.ExtractMentions(release.GetBody()) {
+				if !mapped[strings.ToLower(handle)] && !mentioned[handle] {
+					mentioned[handle] = true
+					unmapped[handle] = append(unmapped[handle], owner+"/"+name)
+				}
+			}
+		}
+	}
+
+	var problems []Problem
+	for _, handle := range sortedKeys(unmapped) {
+		problems = append(problems, Problem{
+			Handle: handle,
+			Reason: "mentioned in " + strings.Join(unmapped[handle], ", ") + " but not mapped",
+		})
+	}
+
+	return problems, nil
+}
+
+// PrintProblems prints the handle problems as a table.
+func PrintProblems(problems []Problem) {
+	tableData := pterm.TableData{{"Handle", "Problem"}}
+	for _, problem := range problems {
+		tableData = append(tableData, []string{problem.Handle, problem.Reason})
+	}
+
+	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
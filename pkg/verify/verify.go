@@ -0,0 +1,124 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mona-actions/gh-migrate-releases/internal/api"
+	"github.com/mona-actions/gh-migrate-releases/internal/releases"
+	"github.com/mona-actions/gh-migrate-releases/internal/repositories"
+	"github.com/pterm/pterm"
+)
+
+func VerifyReleases() {
+	// Releases are compared through the GitHub API, other target platforms aren't supported
+	if api.TargetType() != "github" {
+		pterm.Error.Println("Error: verify only supports GitHub targets, got --target-type " + api.TargetType())
+		os.Exit(1)
+	}
+
+	repositoryList, err := repositories.List()
+	if err != nil {
+		pterm.Error.Printf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	tableData := pterm.TableData{{"Repository", "Tag", "Problem", "Details"}}
+	var totalDifferences, totalErrors int
+
+	for _, repository := range repositoryList {
+		owner, name := repositories.Split(repository)
+
+		verifySpinner, _ := pterm.DefaultSpinner.Start("Verifying releases of repository: ", owner+"/"+name)
+		differences, err := verifyRepository(owner, name)
+		if err != nil {
+			totalErrors++
+			verifySpinner.Fail(fmt.Sprintf("Error verifying repository %s/%s: %v", owner, name, err))
+			continue
+		}
+
+		if len(differences) > 0 {
+			verifySpinner.Fail(fmt.Sprintf("%s/%s: %d discrepancies found", owner, name, len(differences)))
+		} else {
+			verifySpinner.Success(fmt.Sprintf("%s/%s: all releases match", owner, name))
+		}
+
+		for _, difference := range differences {
+			tableData = append(tableData, []string{owner + "/" + name, difference.Tag, difference.Kind, difference.Details})
+		}
+		totalDifferences += len(differences)
+	}
+
+	if totalDifferences > 0 {
+		pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+	}
+
+	pterm.Info.Printf("Repositories: %d\n", len(repositoryList))
+	pterm.Info.Printf("Discrepancies: %d\n", totalDifferences)
+	pterm.Info.Printf("Errors: %d\n", totalErrors)
+
+	if totalDifferences > 0 || totalErrors > 0 {
+		os.Exit(1)
+	}
+}
+
+// verifyRepository compares the releases, latest release and tags of a source repository with its target counterpart.
+func verifyRepository(owner string, repository string) ([]releases.Difference, error) {
+	sourceReleases, err := api.GetSourceRepositoryReleases(owner, repository)
+	if err != nil {
+		return nil, err
+	}
+	targetReleases, err := api.GetTargetRepositoryReleases(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := releases.Compare(sourceReleases, targetReleases)
+
+	sourceLatest, err := api.GetSourceLatestReleaseTag(owner, repository)
+	if err != nil {
+		return nil, err
+	}
+	targetLatest, err := api.GetTargetLatestReleaseTag(repository)
+	if err != nil {
+		return nil, err
+	}
+	if sourceLatest != targetLatest {
+		differences = append(differences, releases.Difference{Tag: sourceLatest, Kind: "latest mismatch",
+			Details: fmt.Sprintf("source latest %q, target latest %q", sourceLatest, targetLatest)})
+	}
+
+	// Compare the commits behind the tags of releases present on both sides
+	targetTags := make(map[string]bool)
+	for _, release := range targetReleases {
+		targetTags[release.GetTagName()] = true
+	}
+	for _, release := range sourceReleases {
+		tag := release.GetTagName()
+		if !targetTags[tag] {
+			continue
+		}
+
+		sourceSHA, err := api.GetSourceTagCommitSHA(owner, repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		targetSHA, err := api.GetTargetTagCommitSHA(repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		if sourceSHA != targetSHA {
+			differences = append(differences, releases.Difference{Tag: tag, Kind: "tag SHA mismatch",
+				Details: fmt.Sprintf("source %s, target %s", displaySHA(sourceSHA), displaySHA(targetSHA))})
+		}
+	}
+
+	return differences, nil
+}
+
+func displaySHA(sha string) string {
+	if sha == "" {
+		return "no tag"
+	}
+	return sha
+}